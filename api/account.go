@@ -1,16 +1,41 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
 
+	"github.com/aronreisx/bubblebank/currency"
 	db "github.com/aronreisx/bubblebank/db/sqlc"
 	"github.com/gin-gonic/gin"
 )
 
+// accountResponse is an account along with its balance formatted
+// according to the exponent of the account's currency, and the part
+// of its balance that is not reserved by holds
+type accountResponse struct {
+	db.Account
+	FormattedBalance string `json:"formatted_balance,omitempty"`
+	AvailableBalance int64  `json:"available_balance"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	rsp := accountResponse{Account: account, AvailableBalance: account.AvailableBalance()}
+	if c, err := currency.Get(account.Currency); err == nil {
+		rsp.FormattedBalance = c.Format(account.Balance)
+	}
+	return rsp
+}
+
+func newAccountResponses(accounts []db.Account) []accountResponse {
+	rsp := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		rsp[i] = newAccountResponse(account)
+	}
+	return rsp
+}
+
 type createAccountRequest struct {
-	Owner    string `json:"owner" binding:"required"`
-	Currency string `json:"currency" binding:"required,oneof=USD EUR"`
+	Currency string `json:"currency" binding:"required,currency"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -20,19 +45,33 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreateAccountParams{
-		Owner:    req.Owner,
-		Currency: req.Currency,
-		Balance:  0,
+	idempotent, ok := server.startIdempotentRequest(ctx, req)
+	if !ok {
+		return
+	}
+	defer idempotent.release(ctx)
+
+	authPayload := authorizationPayload(ctx)
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    authPayload.Username,
+			Currency: req.Currency,
+			Balance:  0,
+		},
+	}
+	if idempotent != nil {
+		arg.AfterCreate = func(q db.Querier, account db.Account) error {
+			return idempotent.complete(ctx, q, http.StatusOK, newAccountResponse(account))
+		}
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.CreateAccountTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorStatus(err), errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type getAccountRequest struct {
@@ -48,21 +87,84 @@ func (server *Server) getAccount(ctx *gin.Context) {
 
 	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type updateAccountStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active frozen closed"`
+}
+
+// updateAccountStatus lets users freeze or close their own accounts.
+// Unfreezing is left to bank staff, in case the account was frozen because it was compromised.
+func (server *Server) updateAccountStatus(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if req.Status == db.AccountStatusActive {
+		err := errors.New("only bank staff can unfreeze an account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	server.setAccountStatus(ctx, account, req.Status)
+}
+
+// setAccountStatus moves an account to the given status and writes the response
+func (server *Server) setAccountStatus(ctx *gin.Context, account db.Account, status string) {
+	// Deposits and withdrawals need the settlement accounts
+	if account.Owner == db.SystemAccountOwner {
+		err := errors.New("cannot change the status of a settlement account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.UpdateAccountStatusTx(ctx, db.UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    status,
+	})
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type listAccountsRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	pageRequest
 }
 
 func (server *Server) listAccounts(ctx *gin.Context) {
@@ -72,16 +174,32 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := authorizationPayload(ctx)
+	scope := cursorScope(ctx.Request.URL.Path, authPayload.Username)
+	page, err := newPage(server.cursorKey, scope, req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := db.ListAccountsParams{
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Owner:           authPayload.Username,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit,
+		Offset:          page.Offset,
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(errorStatus(err), errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	writePage(ctx, server.cursorKey, scope, req.pageRequest, newAccountResponses(accounts), accountCursor)
+}
+
+// accountCursor returns the pagination key of an account
+func accountCursor(account accountResponse) cursor {
+	return cursor{CreatedAt: account.CreatedAt, ID: account.ID}
 }
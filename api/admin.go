@@ -0,0 +1,152 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type adminListAccountsRequest struct {
+	Owner string `form:"owner" binding:"omitempty,alphanum"`
+	pageRequest
+}
+
+// adminListAccounts lists the accounts of every user, or only the accounts
+// whose owner starts with the owner query parameter when it is given
+func (server *Server) adminListAccounts(ctx *gin.Context) {
+	var req adminListAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scope := cursorScope(ctx.Request.URL.Path, req.Owner)
+	page, err := newPage(server.cursorKey, scope, req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var accounts []db.Account
+	if req.Owner == "" {
+		accounts, err = server.store.ListAllAccounts(ctx, db.ListAllAccountsParams{
+			CursorCreatedAt: page.CursorCreatedAt,
+			CursorID:        page.CursorID,
+			Limit:           page.Limit,
+			Offset:          page.Offset,
+		})
+	} else {
+		accounts, err = server.store.SearchAccountsByOwner(ctx, db.SearchAccountsByOwnerParams{
+			OwnerPrefix:     req.Owner,
+			CursorCreatedAt: page.CursorCreatedAt,
+			CursorID:        page.CursorID,
+			Limit:           page.Limit,
+			Offset:          page.Offset,
+		})
+	}
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	writePage(ctx, server.cursorKey, scope, req.pageRequest, newAccountResponses(accounts), accountCursor)
+}
+
+// adminGetAccount returns any account, regardless of its owner
+func (server *Server) adminGetAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// adminListEntries lists the entries of any account, regardless of its owner
+func (server *Server) adminListEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.writeAccountEntries(ctx, account, req)
+}
+
+// adminUpdateAccountStatus freezes, unfreezes or closes any account
+func (server *Server) adminUpdateAccountStatus(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.setAccountStatus(ctx, account, req.Status)
+}
+
+type updateUserRoleURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=depositor banker admin"`
+}
+
+// updateUserRole changes the role of a user. The new role is carried by the
+// user's tokens from their next login or token renewal.
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	var uri updateUserRoleURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/aronreisx/bubblebank/currency"
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/fx"
+	"github.com/aronreisx/bubblebank/numeric"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrDepositLimitExceeded is returned when a single deposit is above the configured maximum
+var ErrDepositLimitExceeded = errors.New("deposit limit exceeded")
+
+// parseCashLimitRates parses the rates cash limits are converted with, such as "JPY:150,KWD:0.3".
+// Limits are configured in whole units of a reference currency, a rate is the number of
+// units of a currency worth one unit of the reference currency.
+func parseCashLimitRates(s string) (map[string]*big.Rat, error) {
+	rates := make(map[string]*big.Rat)
+	if s == "" {
+		return rates, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		code, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || !currency.IsSupported(code) {
+			return nil, fmt.Errorf("invalid cash limit rate %q", pair)
+		}
+
+		rate, err := fx.ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cash limit rate %q: %w", pair, err)
+		}
+		rates[code] = rate
+	}
+
+	return rates, nil
+}
+
+// cashLimit converts a limit configured in whole units of the reference currency into
+// minor units of c. Currencies without a rate are limited to the same number of units.
+// A positive limit never rounds down to zero, which stands for no limit.
+func (server *Server) cashLimit(c currency.Currency, units int64) int64 {
+	rate, ok := server.cashLimitRates[c.Code]
+	if !ok || units <= 0 {
+		return c.MinorUnits(units)
+	}
+
+	amount := new(big.Rat).Mul(new(big.Rat).SetInt64(units), rate)
+	amount.Mul(amount, numeric.Pow10(c.Exponent))
+
+	limit := new(big.Int).Quo(amount.Num(), amount.Denom())
+	switch {
+	case !limit.IsInt64():
+		return math.MaxInt64
+	case limit.Sign() <= 0:
+		return 1
+	default:
+		return limit.Int64()
+	}
+}
+
+type cashRequest struct {
+	Currency string `json:"currency" binding:"required,currency"`
+	Memo     string `json:"memo" binding:"max=140"`
+	// DecimalAmount is the amount in the currency's major unit, such as "12.34", given instead of Amount
+	DecimalAmount string `json:"decimal_amount" binding:"max=32"`
+	Amount        int64  `json:"amount" binding:"required_without=DecimalAmount,excluded_with=DecimalAmount,omitempty,gt=0"`
+}
+
+type cashResponse struct {
+	Account accountResponse `json:"account"`
+	Entry   db.Entry        `json:"entry"`
+}
+
+// createDeposit credits an account from the settlement account of its currency.
+// Deposits record cash taken in by bank staff, they can be made to any customer account.
+func (server *Server) createDeposit(ctx *gin.Context) {
+	server.postCash(ctx, db.EntryKindDeposit)
+}
+
+// createWithdrawal debits an account of the authenticated user to the settlement account of its currency
+func (server *Server) createWithdrawal(ctx *gin.Context) {
+	server.postCash(ctx, db.EntryKindWithdrawal)
+}
+
+func (server *Server) postCash(ctx *gin.Context, kind string) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req cashRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The currency was validated on binding
+	c, err := currency.Get(req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	req.Amount, err = requestAmount(req.Currency, req.Amount, req.DecimalAmount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	maxDeposit := server.cashLimit(c, server.config.MaxDepositAmount)
+	if kind == db.EntryKindDeposit && maxDeposit > 0 && req.Amount > maxDeposit {
+		err := fmt.Errorf("%w: at most %s %s per deposit", ErrDepositLimitExceeded, c.Format(maxDeposit), c.Code)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+
+	idempotent, ok := server.startIdempotentRequest(ctx, req)
+	if !ok {
+		return
+	}
+	defer idempotent.release(ctx)
+
+	account, valid := server.validAccount(ctx, uri.ID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if kind == db.EntryKindWithdrawal && account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	dailyLimit := server.config.DailyWithdrawalLimit
+	if kind == db.EntryKindDeposit {
+		dailyLimit = server.config.DailyDepositLimit
+	}
+
+	arg := db.CashTxParams{
+		AccountID:  account.ID,
+		Kind:       kind,
+		Amount:     req.Amount,
+		Memo:       req.Memo,
+		DailyLimit: server.cashLimit(c, dailyLimit),
+	}
+	if idempotent != nil {
+		arg.AfterPost = func(q db.Querier, result db.CashTxResult) error {
+			return idempotent.complete(ctx, q, http.StatusOK, newCashResponse(result))
+		}
+	}
+
+	result, err := server.store.CashTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.metrics.ObserveCash(account.Currency, kind)
+	ctx.JSON(http.StatusOK, newCashResponse(result))
+}
+
+func newCashResponse(result db.CashTxResult) cashResponse {
+	return cashResponse{
+		Account: newAccountResponse(result.Account),
+		Entry:   result.Entry,
+	}
+}
@@ -0,0 +1,501 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aronreisx/bubblebank/currency"
+	mockdb "github.com/aronreisx/bubblebank/db/mock"
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
+)
+
+func TestCashAPI(t *testing.T) {
+	account := createRandomAccount()
+	account.Currency = "USD"
+	amount := int64(100)
+
+	depositResult := db.CashTxResult{
+		Account: account,
+		Entry: db.Entry{
+			ID:        1,
+			AccountID: account.ID,
+			Amount:    amount,
+			Kind:      db.EntryKindDeposit,
+			Memo:      "paycheck",
+		},
+	}
+	depositResult.Account.Balance += amount
+
+	withdrawalResult := db.CashTxResult{
+		Account: account,
+		Entry: db.Entry{
+			ID:        2,
+			AccountID: account.ID,
+			Amount:    -amount,
+			Kind:      db.EntryKindWithdrawal,
+		},
+	}
+	withdrawalResult.Account.Balance -= amount
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		operation     string
+		accountID     int64
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "DepositOK",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD", "memo": "paycheck"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID:  account.ID,
+					Kind:       db.EntryKindDeposit,
+					Amount:     amount,
+					Memo:       "paycheck",
+					DailyLimit: 2000,
+				}
+				store.EXPECT().CashTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(depositResult, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCash(t, recorder.Body, depositResult)
+			},
+		},
+		{
+			name:      "WithdrawalOK",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID:  account.ID,
+					Kind:       db.EntryKindWithdrawal,
+					Amount:     amount,
+					DailyLimit: 500,
+				}
+				store.EXPECT().CashTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(withdrawalResult, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCash(t, recorder.Body, withdrawalResult)
+			},
+		},
+		{
+			name:      "DepositLimitExceeded",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": 1001, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "DepositLimitExceededJPY",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": 1501, "currency": "JPY"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// Limits are in dollars converted at 150 yen, the yen has no minor unit
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "DailyDepositLimitExceeded",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrDailyDepositLimitExceeded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "DepositByDepositor",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "WithdrawalLimitExceeded",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrWithdrawalLimitExceeded)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "InsufficientFunds",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "AccountNotFound",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "CurrencyMismatch",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "EUR"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "MemoTooLong",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD", "memo": util.RandomString(141)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NegativeAmount",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": -amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "DecimalAmount",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"decimal_amount": "1.00", "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID:  account.ID,
+					Kind:       db.EntryKindWithdrawal,
+					Amount:     amount,
+					DailyLimit: 500,
+				}
+				store.EXPECT().CashTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(withdrawalResult, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCash(t, recorder.Body, withdrawalResult)
+			},
+		},
+		{
+			name:      "DecimalAmountTooPrecise",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"decimal_amount": "1.001", "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NegativeDecimalAmount",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"decimal_amount": "-1.00", "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "AmountAndDecimalAmount",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "decimal_amount": "1.00", "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "MissingAmount",
+			operation: "withdrawals",
+			accountID: account.ID,
+			body:      gin.H{"currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			operation: "deposits",
+			accountID: 0,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InternalError",
+			operation: "deposits",
+			accountID: account.ID,
+			body:      gin.H{"amount": amount, "currency": "USD"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "teller", util.BankerRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CashTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.MaxDepositAmount = 10
+			server.config.DailyDepositLimit = 20
+			server.config.DailyWithdrawalLimit = 5
+			server.cashLimitRates = map[string]*big.Rat{"JPY": big.NewRat(150, 1)}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			requestURL := fmt.Sprintf("/accounts/%d/%s", tc.accountID, tc.operation)
+			request, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchCash(t *testing.T, body *bytes.Buffer, result db.CashTxResult) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotResponse cashResponse
+	err = json.Unmarshal(data, &gotResponse)
+	require.NoError(t, err)
+	require.Equal(t, newCashResponse(result), gotResponse)
+}
+
+func TestCashLimit(t *testing.T) {
+	rates, err := parseCashLimitRates("JPY:150, KWD:0.3,EUR:0.9")
+	require.NoError(t, err)
+
+	server := &Server{cashLimitRates: rates}
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		code  string
+		units int64
+		limit int64
+	}{
+		{code: "USD", units: 10, limit: 1000},
+		{code: "JPY", units: 10, limit: 1500},
+		{code: "KWD", units: 10, limit: 3000},
+		{code: "EUR", units: 10, limit: 900},
+		{code: "JPY", units: 0, limit: 0},
+		{code: "JPY", units: math.MaxInt64, limit: math.MaxInt64},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		c, err := currency.Get(tc.code)
+		require.NoError(t, err)
+		require.Equal(t, tc.limit, server.cashLimit(c, tc.units), "%d %s", tc.units, tc.code)
+	}
+
+	for _, invalid := range []string{"JPY", "XXX:1", "JPY:0", "JPY:-1", "JPY:abc"} {
+		_, err := parseCashLimitRates(invalid)
+		require.Error(t, err, invalid)
+	}
+}
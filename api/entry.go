@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type listEntriesRequest struct {
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty,gtfield=From"`
+	Direction string    `form:"direction" binding:"omitempty,oneof=credit debit"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=asc desc"`
+	MinAmount int64     `form:"min_amount" binding:"omitempty,gt=0"`
+	MaxAmount int64     `form:"max_amount" binding:"omitempty,gt=0,gtefield=MinAmount"`
+	pageRequest
+}
+
+// listEntries returns the statement of an account owned by the authenticated user
+func (server *Server) listEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	server.writeAccountEntries(ctx, account, req)
+}
+
+// writeAccountEntries lists the entries of account matching the filters of req
+func (server *Server) writeAccountEntries(ctx *gin.Context, account db.Account, req listEntriesRequest) {
+	// A cursor is meaningless in the other sort order
+	sort := "asc"
+	if req.Sort == "desc" {
+		sort = "desc"
+	}
+	scope := cursorScope(ctx.Request.URL.Path, sort)
+	page, err := newPage(server.cursorKey, scope, req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListAccountEntriesParams{
+		AccountID:       account.ID,
+		FromTime:        pgtype.Timestamptz{Time: req.From, Valid: !req.From.IsZero()},
+		ToTime:          pgtype.Timestamptz{Time: req.To, Valid: !req.To.IsZero()},
+		Direction:       pgtype.Text{String: req.Direction, Valid: req.Direction != ""},
+		MinAmount:       pgtype.Int8{Int64: req.MinAmount, Valid: req.MinAmount > 0},
+		MaxAmount:       pgtype.Int8{Int64: req.MaxAmount, Valid: req.MaxAmount > 0},
+		SortDesc:        req.Sort == "desc",
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit,
+		Offset:          page.Offset,
+	}
+
+	entries, err := server.store.ListAccountEntries(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	writePage(ctx, server.cursorKey, scope, req.pageRequest, entries, entryCursor)
+}
+
+// entryCursor returns the pagination key of a statement entry
+func entryCursor(entry db.ListAccountEntriesRow) cursor {
+	return cursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+}
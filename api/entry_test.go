@@ -0,0 +1,326 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/aronreisx/bubblebank/db/mock"
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
+)
+
+func TestListEntriesAPI(t *testing.T) {
+	account := createRandomAccount()
+	n := 5
+	entries := randomStatement(account, n)
+
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		query         url.Values
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: url.Values{
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.ListAccountEntriesParams{
+					AccountID: account.ID,
+					Limit:     int32(n),
+					Offset:    0,
+				}
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEntries(t, recorder.Body, entries)
+			},
+		},
+		{
+			name: "WithFilters",
+			query: url.Values{
+				"from":       {from.Format(time.RFC3339)},
+				"to":         {to.Format(time.RFC3339)},
+				"direction":  {"debit"},
+				"min_amount": {"10"},
+				"max_amount": {"100"},
+				"sort":       {"desc"},
+				"page_id":    {"2"},
+				"page_size":  {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.ListAccountEntriesParams{
+					AccountID: account.ID,
+					FromTime:  pgtype.Timestamptz{Time: from, Valid: true},
+					ToTime:    pgtype.Timestamptz{Time: to, Valid: true},
+					Direction: pgtype.Text{String: "debit", Valid: true},
+					MinAmount: pgtype.Int8{Int64: 10, Valid: true},
+					MaxAmount: pgtype.Int8{Int64: 100, Valid: true},
+					SortDesc:  true,
+					Limit:     int32(n),
+					Offset:    int32(n),
+				}
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEntries(t, recorder.Body, entries)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			query: url.Values{
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			query: url.Values{
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			query: url.Values{
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDirection",
+			query: url.Values{
+				"direction": {"sideways"},
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTimeRange",
+			query: url.Values{
+				"from":      {to.Format(time.RFC3339)},
+				"to":        {from.Format(time.RFC3339)},
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTimeFormat",
+			query: url.Values{
+				"from":      {"yesterday"},
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmountRange",
+			query: url.Values{
+				"min_amount": {"100"},
+				"max_amount": {"10"},
+				"page_id":    {"1"},
+				"page_size":  {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			query: url.Values{
+				"page_id":   {"1"},
+				"page_size": {fmt.Sprint(n)},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+				store.EXPECT().
+					ListAccountEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountEntriesRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			requestURL := fmt.Sprintf("/accounts/%d/entries?%s", account.ID, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// randomStatement builds n entries of account with a consistent running balance
+func randomStatement(account db.Account, n int) []db.ListAccountEntriesRow {
+	entries := make([]db.ListAccountEntriesRow, n)
+	var balance int64
+	for i := 0; i < n; i++ {
+		amount := util.RandomInt(-100, 100)
+		balance += amount
+		entries[i] = db.ListAccountEntriesRow{
+			ID:             int64(i + 1),
+			AccountID:      account.ID,
+			Amount:         amount,
+			RunningBalance: balance,
+		}
+	}
+	return entries
+}
+
+func requireBodyMatchEntries(t *testing.T, body *bytes.Buffer, entries []db.ListAccountEntriesRow) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotEntries []db.ListAccountEntriesRow
+	err = json.Unmarshal(data, &gotEntries)
+	require.NoError(t, err)
+	require.Equal(t, entries, gotEntries)
+}
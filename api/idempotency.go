@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+	// idempotencyLockTimeout is how long a request may hold a key before a retry can take it over
+	idempotencyLockTimeout = time.Minute
+)
+
+// idempotencyStatusCompleted is the status of a key whose response was stored
+const idempotencyStatusCompleted = "completed"
+
+// ErrIdempotencyKeyTakenOver is returned when a request held its key past
+// idempotencyLockTimeout and a retry took it over before the request completed
+var ErrIdempotencyKeyTakenOver = fmt.Errorf("%s was taken over by a retry of the request", idempotencyKeyHeader)
+
+// idempotentRequest is a mutating request holding an idempotency key.
+// A nil *idempotentRequest stands for a request sent without a key.
+type idempotentRequest struct {
+	// lockedAt tells the claim of this request apart from the claim of a retry taking the key over
+	lockedAt time.Time
+	store    db.Store
+	username string
+	key      string
+}
+
+// startIdempotentRequest claims the Idempotency-Key of the request, if any, for req.
+// When the key was already used it writes the response itself and reports that the
+// handler must stop: the original response for a replay, 409 while the original
+// request is in flight and 422 when the key was used for a different request.
+func (server *Server) startIdempotentRequest(ctx *gin.Context, req any) (*idempotentRequest, bool) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		return nil, true
+	}
+
+	if len(key) > maxIdempotencyKeyLen {
+		err := fmt.Errorf("%s header must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLen)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return nil, false
+	}
+
+	requestHash, err := hashRequest(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+
+	username := authorizationPayload(ctx).Username
+	claimed, err := server.store.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash,
+		StaleBefore: time.Now().Add(-idempotencyLockTimeout),
+	})
+	if err == nil {
+		return &idempotentRequest{lockedAt: claimed.LockedAt, store: server.store, username: username, key: key}, true
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return nil, false
+	}
+
+	// The key is taken, tell the client what happened to the original request
+	existing, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: username,
+		Key:      key,
+	})
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return nil, false
+	}
+
+	switch {
+	case existing.RequestHash != requestHash:
+		err := fmt.Errorf("%s was already used for a different request", idempotencyKeyHeader)
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	case existing.Status != idempotencyStatusCompleted:
+		err := fmt.Errorf("a request with this %s is already in progress", idempotencyKeyHeader)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		ctx.Data(int(existing.ResponseCode), gin.MIMEJSON+"; charset=utf-8", existing.ResponseBody)
+	}
+	return nil, false
+}
+
+// hashRequest identifies a request by its path and body, so that a key cannot be reused for another request.
+// The path rather than the route is hashed, so that requests on different resources never match.
+func hashRequest(ctx *gin.Context, req any) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Request.Method + " " + ctx.Request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// complete stores the response of the request. It is meant to run inside the
+// transaction of the request, so that the response is stored if and only if
+// the request's changes are committed. It returns ErrIdempotencyKeyTakenOver,
+// rolling the request back, when a retry took the key over.
+func (r *idempotentRequest) complete(ctx *gin.Context, q db.Querier, code int, body any) error {
+	if r == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		Username:     r.username,
+		Key:          r.key,
+		ResponseCode: int32(code), // #nosec G115 -- HTTP status codes fit in an int32
+		ResponseBody: data,
+		LockedAt:     r.lockedAt,
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return ErrIdempotencyKeyTakenOver
+	}
+	return err
+}
+
+// release frees the key of a request that failed, so that the client can retry it.
+// Keys of completed requests are kept.
+func (r *idempotentRequest) release(ctx *gin.Context) {
+	if r == nil || ctx.Writer.Status() < http.StatusBadRequest {
+		return
+	}
+
+	// Best effort: a key that is not released is taken over after idempotencyLockTimeout
+	_ = r.store.DeleteIdempotencyKey(context.WithoutCancel(ctx), db.DeleteIdempotencyKeyParams{
+		Username: r.username,
+		Key:      r.key,
+		LockedAt: r.lockedAt,
+	})
+}
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/aronreisx/bubblebank/db/mock"
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/util"
+)
+
+func TestIdempotentCreateAccountAPI(t *testing.T) {
+	account := createRandomAccount()
+	account.Balance = 0
+	account.Currency = "USD"
+
+	key := util.RandomString(16)
+	keyParams := db.GetIdempotencyKeyParams{Username: account.Owner, Key: key}
+	lockedAt := time.Now().Truncate(time.Microsecond)
+
+	accountJSON, err := json.Marshal(newAccountResponse(account))
+	require.NoError(t, err)
+
+	// expectTakenKey makes the key already claimed, by a request with the same hash unless otherHash is set
+	expectTakenKey := func(store *mockdb.MockStore, status string, otherHash bool) {
+		var requestHash string
+		store.EXPECT().
+			CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+				requestHash = arg.RequestHash
+				return db.IdempotencyKey{}, db.ErrRecordNotFound
+			})
+		store.EXPECT().
+			GetIdempotencyKey(gomock.Any(), gomock.Eq(keyParams)).
+			Times(1).
+			DoAndReturn(func(_ context.Context, _ db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+				existing := db.IdempotencyKey{
+					Username:     account.Owner,
+					Key:          key,
+					RequestHash:  requestHash,
+					Status:       status,
+					ResponseCode: http.StatusOK,
+					ResponseBody: accountJSON,
+				}
+				if otherHash {
+					existing.RequestHash = "other"
+				}
+				return existing, nil
+			})
+		store.EXPECT().
+			CreateAccountTx(gomock.Any(), gomock.Any()).
+			Times(0)
+	}
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+						require.Equal(t, account.Owner, arg.Username)
+						require.Equal(t, key, arg.Key)
+						require.NotEmpty(t, arg.RequestHash)
+						require.WithinDuration(t, time.Now().Add(-idempotencyLockTimeout), arg.StaleBefore, time.Second)
+						return db.IdempotencyKey{Username: arg.Username, Key: arg.Key, LockedAt: lockedAt}, nil
+					})
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAccountTxParams) (db.Account, error) {
+						require.NotNil(t, arg.AfterCreate)
+						return account, arg.AfterCreate(store, account)
+					})
+				store.EXPECT().
+					CompleteIdempotencyKey(gomock.Any(), gomock.Eq(db.CompleteIdempotencyKeyParams{
+						Username:     account.Owner,
+						Key:          key,
+						ResponseCode: http.StatusOK,
+						ResponseBody: accountJSON,
+						LockedAt:     lockedAt,
+					})).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name: "TakenOverByRetry",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{Username: account.Owner, Key: key, LockedAt: lockedAt}, nil)
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAccountTxParams) (db.Account, error) {
+						return db.Account{}, arg.AfterCreate(store, account)
+					})
+				// A retry holds the key since a later time, so nothing matches the original claim
+				store.EXPECT().
+					CompleteIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Username: account.Owner,
+						Key:      key,
+						LockedAt: lockedAt,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "Replay",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectTakenKey(store, idempotencyStatusCompleted, false)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, accountJSON, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "InFlight",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectTakenKey(store, "in_progress", false)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "DifferentRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				expectTakenKey(store, idempotencyStatusCompleted, true)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "FailureReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, &db.Error{Kind: db.ErrUniqueViolation, Err: &pgconn.PgError{Code: "23505"}})
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Username: account.Owner,
+						Key:      key,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "KeyTooLong",
+			key:  strings.Repeat("k", maxIdempotencyKeyLen+1),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(gin.H{"currency": account.Currency})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			request.Header.Set(idempotencyKeyHeader, tc.key)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, util.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestIdempotentTransferAPI(t *testing.T) {
+	amount := int64(10)
+
+	account1 := createRandomAccount()
+	account2 := createRandomAccount()
+	account2.ID = account1.ID + 1
+	account1.Currency = "USD"
+	account2.Currency = "USD"
+
+	result := db.TransferTxResult{
+		Transfer: db.Transfer{
+			ID:            util.RandomInt(1, 1000),
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		},
+		FromAccount: account1,
+		ToAccount:   account2,
+	}
+
+	resultJSON, err := json.Marshal(newTransferResponse(result))
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	key := util.RandomString(16)
+
+	store.EXPECT().
+		CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.IdempotencyKey{Username: account1.Owner, Key: key}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			require.NotNil(t, arg.AfterTransfer)
+			return result, arg.AfterTransfer(store, result)
+		})
+	store.EXPECT().
+		CompleteIdempotencyKey(gomock.Any(), gomock.Eq(db.CompleteIdempotencyKeyParams{
+			Username:     account1.Owner,
+			Key:          key,
+			ResponseCode: http.StatusOK,
+			ResponseBody: resultJSON,
+		})).
+		Times(1).
+		Return(db.IdempotencyKey{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          amount,
+		"currency":        "USD",
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	request.Header.Set(idempotencyKeyHeader, key)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account1.Owner, util.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchTransferResult(t, recorder.Body, result)
+}
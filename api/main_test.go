@@ -3,10 +3,29 @@ package api
 import (
 	"os"
 	"testing"
+	"time"
 
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
 )
 
+func newTestServer(t *testing.T, store db.Store, opts ...ServerOption) *Server {
+	config := util.Config{
+		TokenType:            token.TypePaseto,
+		TokenSigningKey:      util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	server, err := NewServer(config, store, opts...)
+	require.NoError(t, err)
+
+	return server
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aronreisx/bubblebank/logging"
+	"github.com/aronreisx/bubblebank/metrics"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+	requestIDHeaderKey      = "X-Request-ID"
+	// maxRequestIDLength bounds the request IDs taken from callers, as they end up in every log line
+	maxRequestIDLength = 128
+)
+
+// authMiddleware creates a gin middleware that requires a valid bearer token
+// and stores its payload in the context under authorizationPayloadKey
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) != 2 {
+			err := errors.New("invalid authorization header format")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		accessToken := fields[1]
+		// Refresh tokens outlive their revoked sessions, they cannot authorize requests
+		payload, err := tokenMaker.VerifyToken(accessToken, token.PurposeAccess)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
+
+// roleMiddleware creates a gin middleware that only lets through requests whose
+// token carries one of the given roles. It must run after authMiddleware.
+func roleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := authorizationPayload(ctx)
+		if payload == nil || !slices.Contains(roles, payload.Role) {
+			err := errors.New("permission denied")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// authorizationPayload returns the token payload stored in the context by authMiddleware
+func authorizationPayload(ctx *gin.Context) *token.Payload {
+	payload, _ := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	return payload
+}
+
+// metricsMiddleware creates a gin middleware recording the count and latency of
+// requests by route, so that paths with IDs do not each get their own series
+func metricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// loggingMiddleware creates a gin middleware that tags every request with an ID,
+// taken from the X-Request-ID header of the caller or generated, and echoes it back.
+// The request context carries a logger annotated with the ID and the trace ID, so
+// that the store logs on behalf of the request. One line is logged per request.
+func loggingMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(requestIDHeaderKey, requestID)
+
+		requestLogger := logger.With(slog.String("request_id", requestID))
+		if spanContext := trace.SpanContextFromContext(ctx.Request.Context()); spanContext.HasTraceID() {
+			requestLogger = requestLogger.With(slog.String("trace_id", spanContext.TraceID().String()))
+		}
+		ctx.Request = ctx.Request.WithContext(logging.WithLogger(ctx.Request.Context(), requestLogger))
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			if payload, ok := payload.(*token.Payload); ok {
+				attrs = append(attrs, slog.String("username", payload.Username))
+			}
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		requestLogger.LogAttrs(ctx.Request.Context(), level, "request handled", attrs...)
+	}
+}
+
+// validRequestID reports whether a request ID sent by a caller can be logged as is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/logging"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func addAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	authorizationType string,
+	username string,
+	role string,
+	duration time.Duration,
+) {
+	accessToken, payload, err := tokenMaker.CreateToken(username, role, token.PurposeAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, accessToken)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	username := "user"
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "unsupported", username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAuthorizationFormat",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "", username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken(username, util.DepositorRole, token.PurposeRefresh, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.DepositorRole, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker),
+				func(ctx *gin.Context) {
+					payload, ok := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+					require.True(t, ok)
+					require.Equal(t, username, payload.Username)
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	username := "user"
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Banker",
+			role: util.BankerRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Admin",
+			role: util.AdminRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Depositor",
+			role: util.DepositorRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoRole",
+			role: "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			rolePath := "/role"
+			server.router.GET(
+				rolePath,
+				authMiddleware(server.tokenMaker),
+				roleMiddleware(util.BankerRole, util.AdminRole),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, rolePath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	// Requests are labeled by route rather than by path
+	for _, path := range []string{"/health", "/health", "/unknown"} {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	require.Contains(t, body, `bubblebank_http_requests_total{method="GET",route="/health",status="200"} 2`)
+	require.Contains(t, body, `bubblebank_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, body, `bubblebank_http_request_duration_seconds_bucket{method="GET",route="/health",status="200"`)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	server := newTestServer(t, nil)
+
+	// The request continues the trace of its caller
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	request, err := http.NewRequest(http.MethodGet, "/health", nil)
+	require.NoError(t, err)
+	request.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	server.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "/health", spans[0].Name())
+	require.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	username := "user"
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name      string
+		setupAuth bool
+		requestID string
+		checkLogs func(t *testing.T, recorder *httptest.ResponseRecorder, lines []map[string]any)
+	}{
+		{
+			name:      "GeneratedID",
+			setupAuth: true,
+			checkLogs: func(t *testing.T, recorder *httptest.ResponseRecorder, lines []map[string]any) {
+				requestID := recorder.Header().Get(requestIDHeaderKey)
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+
+				// The handler logs through the logger of the request
+				require.Len(t, lines, 2)
+				require.Equal(t, "from handler", lines[0]["msg"])
+				require.Equal(t, requestID, lines[0]["request_id"])
+
+				require.Equal(t, "request handled", lines[1]["msg"])
+				require.Equal(t, requestID, lines[1]["request_id"])
+				require.Equal(t, http.MethodGet, lines[1]["method"])
+				require.Equal(t, "/logged", lines[1]["route"])
+				require.Equal(t, float64(http.StatusOK), lines[1]["status"])
+				require.Equal(t, username, lines[1]["username"])
+				require.Contains(t, lines[1], "latency")
+			},
+		},
+		{
+			name:      "PropagatedID",
+			setupAuth: true,
+			requestID: "caller-request-1",
+			checkLogs: func(t *testing.T, recorder *httptest.ResponseRecorder, lines []map[string]any) {
+				require.Equal(t, "caller-request-1", recorder.Header().Get(requestIDHeaderKey))
+				require.Len(t, lines, 2)
+				require.Equal(t, "caller-request-1", lines[0]["request_id"])
+				require.Equal(t, "caller-request-1", lines[1]["request_id"])
+			},
+		},
+		{
+			name:      "InvalidID",
+			setupAuth: true,
+			requestID: "forged\nline",
+			checkLogs: func(t *testing.T, recorder *httptest.ResponseRecorder, lines []map[string]any) {
+				requestID := recorder.Header().Get(requestIDHeaderKey)
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+				require.Len(t, lines, 2)
+				require.Equal(t, requestID, lines[1]["request_id"])
+			},
+		},
+		{
+			name:      "Unauthenticated",
+			setupAuth: false,
+			checkLogs: func(t *testing.T, recorder *httptest.ResponseRecorder, lines []map[string]any) {
+				require.Len(t, lines, 1)
+				require.Equal(t, float64(http.StatusUnauthorized), lines[0]["status"])
+				require.NotContains(t, lines[0], "username")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := logging.New(&buf, logging.Config{})
+			require.NoError(t, err)
+
+			server := newTestServer(t, nil, WithLogger(logger))
+
+			requestURL := "/logged"
+			server.router.GET(
+				requestURL,
+				authMiddleware(server.tokenMaker),
+				func(ctx *gin.Context) {
+					// Handlers pass the gin context on to the store
+					logging.FromContext(ctx).Info("from handler")
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+			require.NoError(t, err)
+
+			if tc.requestID != "" {
+				request.Header.Set(requestIDHeaderKey, tc.requestID)
+			}
+			if tc.setupAuth {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.DepositorRole, time.Minute)
+			}
+			server.router.ServeHTTP(recorder, request)
+
+			var lines []map[string]any
+			decoder := json.NewDecoder(&buf)
+			for decoder.More() {
+				var line map[string]any
+				require.NoError(t, decoder.Decode(&line))
+				lines = append(lines, line)
+			}
+			tc.checkLogs(t, recorder, lines)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultPageSize = 20
+	// cursorKeyContext separates the cursor signing key from the token signing key it is derived from
+	cursorKeyContext = "bubblebank pagination cursor"
+)
+
+// ErrInvalidCursor is returned when a cursor was not issued by this server for the
+// same list, or was tampered with
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// pageRequest holds the pagination parameters shared by the list endpoints.
+// Requests with a page_id keep the legacy OFFSET pagination and plain list response,
+// every other request is paginated by cursor and gets a listResponse envelope.
+type pageRequest struct {
+	Cursor   string `form:"cursor" binding:"excluded_with=PageID"`
+	PageID   int32  `form:"page_id" binding:"omitempty,min=1"`
+	PageSize int32  `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// legacy reports whether the request uses page_id based pagination
+func (req pageRequest) legacy() bool {
+	return req.PageID > 0
+}
+
+// limit returns the requested page size or the default one
+func (req pageRequest) limit() int32 {
+	if req.PageSize == 0 {
+		return defaultPageSize
+	}
+	return req.PageSize
+}
+
+// offset returns the number of rows to skip for legacy pagination
+func (req pageRequest) offset() int32 {
+	return (req.PageID - 1) * req.limit()
+}
+
+// listResponse is the envelope of cursor paginated list responses
+type listResponse[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the (created_at, id) key of the last row of a page
+type cursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+// params returns the cursor as nullable query parameters, unset for a zero cursor
+func (c cursor) params() (pgtype.Timestamptz, pgtype.Int8) {
+	if c.ID == 0 {
+		return pgtype.Timestamptz{}, pgtype.Int8{}
+	}
+	return pgtype.Timestamptz{Time: c.CreatedAt, Valid: true}, pgtype.Int8{Int64: c.ID, Valid: true}
+}
+
+// newCursorKey derives the key signing cursors from the token signing key
+func newCursorKey(signingKey string) []byte {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(cursorKeyContext))
+	return mac.Sum(nil)
+}
+
+// cursorScope identifies the list a cursor pages through: the request path, which
+// holds the route and the account ID, followed by the parameters that select or
+// order its rows. A cursor is only accepted by the list it was issued for.
+func cursorScope(path string, params ...string) string {
+	return strings.Join(append([]string{path}, params...), "\n")
+}
+
+// signCursor signs the cursor data together with the scope it was issued for
+func signCursor(key []byte, scope string, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// encodeCursor returns c as an opaque token signed with key for scope
+func encodeCursor(key []byte, scope string, c cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(data) + "." + encoding.EncodeToString(signCursor(key, scope, data)), nil
+}
+
+// decodeCursor verifies that token was signed for scope and returns its cursor.
+// An empty token decodes to the zero cursor, which starts from the first page.
+func decodeCursor(key []byte, scope string, token string) (cursor, error) {
+	var c cursor
+	if token == "" {
+		return c, nil
+	}
+
+	encodedData, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return c, ErrInvalidCursor
+	}
+
+	encoding := base64.RawURLEncoding
+	data, err := encoding.DecodeString(encodedData)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if !hmac.Equal(signature, signCursor(key, scope, data)) {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// page holds the pagination arguments of a list query
+type page struct {
+	CursorCreatedAt pgtype.Timestamptz
+	CursorID        pgtype.Int8
+	Limit           int32
+	Offset          int32
+}
+
+// newPage returns the query arguments for req. Cursor pages fetch one extra row
+// so that newListResponse can tell whether there is a next page.
+func newPage(key []byte, scope string, req pageRequest) (page, error) {
+	if req.legacy() {
+		return page{Limit: req.limit(), Offset: req.offset()}, nil
+	}
+
+	after, err := decodeCursor(key, scope, req.Cursor)
+	if err != nil {
+		return page{}, err
+	}
+
+	createdAt, id := after.params()
+	return page{CursorCreatedAt: createdAt, CursorID: id, Limit: req.limit() + 1}, nil
+}
+
+// writePage writes rows as a plain list for legacy requests and as a listResponse otherwise
+func writePage[T any](ctx *gin.Context, key []byte, scope string, req pageRequest, rows []T, keyOf func(T) cursor) {
+	if req.legacy() {
+		ctx.JSON(http.StatusOK, rows)
+		return
+	}
+
+	rsp, err := newListResponse(key, scope, rows, req.limit(), keyOf)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// newListResponse wraps the rows of a page fetched with one extra row. The extra row
+// only tells that another page exists, the cursor points at the last row returned.
+func newListResponse[T any](key []byte, scope string, rows []T, limit int32, keyOf func(T) cursor) (listResponse[T], error) {
+	rsp := listResponse[T]{Data: rows}
+	if len(rows) <= int(limit) {
+		return rsp, nil
+	}
+
+	rsp.Data = rows[:limit]
+	nextCursor, err := encodeCursor(key, scope, keyOf(rsp.Data[limit-1]))
+	if err != nil {
+		return rsp, err
+	}
+
+	rsp.NextCursor = nextCursor
+	return rsp, nil
+}
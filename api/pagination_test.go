@@ -0,0 +1,102 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aronreisx/bubblebank/util"
+)
+
+func TestCursor(t *testing.T) {
+	key := newCursorKey(util.RandomString(32))
+	scope := cursorScope("/accounts/1/entries", "asc")
+	c := cursor{
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		ID:        util.RandomInt(1, 1000),
+	}
+
+	token, err := encodeCursor(key, scope, c)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	decoded, err := decodeCursor(key, scope, token)
+	require.NoError(t, err)
+	require.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, c.ID, decoded.ID)
+}
+
+func TestEmptyCursor(t *testing.T) {
+	key := newCursorKey(util.RandomString(32))
+	scope := cursorScope("/accounts/1/entries", "asc")
+
+	decoded, err := decodeCursor(key, scope, "")
+	require.NoError(t, err)
+	require.Zero(t, decoded)
+
+	createdAt, id := decoded.params()
+	require.False(t, createdAt.Valid)
+	require.False(t, id.Valid)
+}
+
+func TestInvalidCursor(t *testing.T) {
+	key := newCursorKey(util.RandomString(32))
+	scope := cursorScope("/accounts/1/entries", "asc")
+
+	token, err := encodeCursor(key, scope, cursor{CreatedAt: time.Now(), ID: 1})
+	require.NoError(t, err)
+
+	otherToken, err := encodeCursor(key, scope, cursor{CreatedAt: time.Now(), ID: 2})
+	require.NoError(t, err)
+
+	data, _, _ := strings.Cut(otherToken, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	otherKey := newCursorKey(util.RandomString(32))
+
+	testCases := map[string]struct {
+		key   []byte
+		scope string
+		token string
+	}{
+		"WrongKey":      {key: otherKey, scope: scope, token: token},
+		"TamperedData":  {key: key, scope: scope, token: data + "." + signature},
+		"MissingDot":    {key: key, scope: scope, token: strings.ReplaceAll(token, ".", "")},
+		"InvalidBase64": {key: key, scope: scope, token: "!!!." + signature},
+		"OtherAccount":  {key: key, scope: cursorScope("/accounts/2/entries", "asc"), token: token},
+		"OtherRoute":    {key: key, scope: cursorScope("/accounts/1/transfers"), token: token},
+		"OtherSort":     {key: key, scope: cursorScope("/accounts/1/entries", "desc"), token: token},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeCursor(tc.key, tc.scope, tc.token)
+			require.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestNewListResponse(t *testing.T) {
+	key := newCursorKey(util.RandomString(32))
+	scope := cursorScope("/accounts/1/entries", "asc")
+	keyOf := func(id int64) cursor {
+		return cursor{ID: id}
+	}
+
+	// A full page plus the extra row has a next page
+	rsp, err := newListResponse(key, scope, []int64{1, 2, 3}, 2, keyOf)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, rsp.Data)
+
+	next, err := decodeCursor(key, scope, rsp.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), next.ID)
+
+	// The last page has no next cursor
+	rsp, err = newListResponse(key, scope, []int64{1, 2}, 2, keyOf)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, rsp.Data)
+	require.Empty(t, rsp.NextCursor)
+}
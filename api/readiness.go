@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessCheckTimeout bounds each dependency check, so that probes answer
+// before the orchestrator gives up on them
+const readinessCheckTimeout = 2 * time.Second
+
+// Readiness states of a server. A server starts, becomes ready once its
+// dependencies are set up, and drains when shutting down. Draining is final.
+const (
+	stateStarting int32 = iota
+	stateReady
+	stateDraining
+)
+
+var stateNames = map[int32]string{
+	stateStarting: "starting",
+	stateReady:    "ready",
+	stateDraining: "draining",
+}
+
+// ReadinessCheck checks a dependency of the server, returning details worth
+// reporting along with the result, such as versions. It must honor the deadline of ctx.
+type ReadinessCheck func(ctx context.Context) (details any, err error)
+
+type readinessCheck struct {
+	check ReadinessCheck
+	name  string
+}
+
+// WithReadinessCheck adds a dependency check to the readiness probe, along with the database check
+func WithReadinessCheck(name string, check ReadinessCheck) ServerOption {
+	return func(server *Server) {
+		server.readinessChecks = append(server.readinessChecks, readinessCheck{name: name, check: check})
+	}
+}
+
+// SetReady marks the server as ready, typically called after migrations complete.
+// A draining server stays draining.
+func (server *Server) SetReady() {
+	server.readiness.CompareAndSwap(stateStarting, stateReady)
+}
+
+// SetNotReady marks the server as draining, so that load balancers stop
+// sending it traffic before it shuts down
+func (server *Server) SetNotReady() {
+	server.readiness.Store(stateDraining)
+}
+
+type checkResult struct {
+	Details   any     `json:"details,omitempty"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type readinessResponse struct {
+	Status string        `json:"status"`
+	Checks []checkResult `json:"checks"`
+}
+
+// readinessCheck handles the GET /ready endpoint
+// This is used by Kubernetes/Docker to determine if the application is ready to receive traffic.
+// A ready server also checks its dependencies, reporting each check and its latency.
+func (server *Server) readinessCheck(ctx *gin.Context) {
+	state := server.readiness.Load()
+	if state != stateReady {
+		ctx.JSON(http.StatusServiceUnavailable, readinessResponse{
+			Status: stateNames[state],
+			Checks: []checkResult{},
+		})
+		return
+	}
+
+	checks := append([]readinessCheck{{name: "database", check: server.pingDatabase}}, server.readinessChecks...)
+	results := make([]checkResult, len(checks))
+
+	// Run the checks concurrently, so that the probe takes as long as the slowest check
+	requestCtx := ctx.Request.Context()
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(requestCtx, c)
+		}()
+	}
+	wg.Wait()
+
+	rsp := readinessResponse{Status: stateNames[stateReady], Checks: results}
+	status := http.StatusOK
+	for _, result := range results {
+		if result.Error != "" {
+			rsp.Status = "not ready"
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	ctx.JSON(status, rsp)
+}
+
+// runCheck runs a dependency check with a timeout and measures its latency
+func runCheck(ctx context.Context, c readinessCheck) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := c.check(ctx)
+	result := checkResult{
+		Details:   details,
+		Name:      c.name,
+		Status:    "up",
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// pingDatabase checks that the store can reach the database
+func (server *Server) pingDatabase(ctx context.Context) (any, error) {
+	return nil, server.store.Ping(ctx)
+}
+
+// MigrationCheck is a ReadinessCheck reporting the migration version of the database
+// against the latest version built into the service, versions returns both.
+// A database behind the service is not ready, a database ahead of it is, as is the case
+// while a newer version of the service is rolled out.
+func MigrationCheck(versions func(ctx context.Context) (current, latest int64, err error)) ReadinessCheck {
+	return func(ctx context.Context) (any, error) {
+		current, latest, err := versions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		details := gin.H{"current_version": current, "latest_version": latest}
+		if current < latest {
+			return details, fmt.Errorf("database is at migration %d, expected %d", current, latest)
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/aronreisx/bubblebank/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// migrationVersions returns a function reporting the given migration versions
+func migrationVersions(current, latest int64) func(ctx context.Context) (int64, int64, error) {
+	return func(ctx context.Context) (int64, int64, error) {
+		return current, latest, nil
+	}
+}
+
+func TestReadinessCheckAPI(t *testing.T) {
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name          string
+		setState      func(server *Server)
+		versions      func(ctx context.Context) (int64, int64, error)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			setState: func(server *Server) { server.SetReady() },
+			versions: migrationVersions(3, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rsp := requireReadinessResponse(t, recorder)
+				require.Equal(t, "ready", rsp.Status)
+				require.Len(t, rsp.Checks, 2)
+
+				require.Equal(t, "database", rsp.Checks[0].Name)
+				require.Equal(t, "up", rsp.Checks[0].Status)
+				require.GreaterOrEqual(t, rsp.Checks[0].LatencyMS, 0.0)
+
+				require.Equal(t, "migrations", rsp.Checks[1].Name)
+				require.Equal(t, "up", rsp.Checks[1].Status)
+				require.Equal(t, map[string]any{"current_version": 3.0, "latest_version": 3.0}, rsp.Checks[1].Details)
+			},
+		},
+		{
+			name:     "DatabaseAhead",
+			setState: func(server *Server) { server.SetReady() },
+			versions: migrationVersions(4, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "DatabaseDown",
+			setState: func(server *Server) { server.SetReady() },
+			versions: migrationVersions(3, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireReadinessResponse(t, recorder)
+				require.Equal(t, "not ready", rsp.Status)
+				require.Equal(t, "down", rsp.Checks[0].Status)
+				require.Equal(t, sql.ErrConnDone.Error(), rsp.Checks[0].Error)
+				require.Equal(t, "up", rsp.Checks[1].Status)
+			},
+		},
+		{
+			name:     "PendingMigrations",
+			setState: func(server *Server) { server.SetReady() },
+			versions: migrationVersions(2, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireReadinessResponse(t, recorder)
+				require.Equal(t, "not ready", rsp.Status)
+				require.Equal(t, "down", rsp.Checks[1].Status)
+				require.NotEmpty(t, rsp.Checks[1].Error)
+			},
+		},
+		{
+			name:     "Starting",
+			setState: func(server *Server) {},
+			versions: migrationVersions(3, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireReadinessResponse(t, recorder)
+				require.Equal(t, "starting", rsp.Status)
+				require.Empty(t, rsp.Checks)
+			},
+		},
+		{
+			name: "Draining",
+			setState: func(server *Server) {
+				server.SetReady()
+				server.SetNotReady()
+				// Draining is final
+				server.SetReady()
+			},
+			versions: migrationVersions(3, 3),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireReadinessResponse(t, recorder)
+				require.Equal(t, "draining", rsp.Status)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, WithReadinessCheck("migrations", MigrationCheck(tc.versions)))
+			tc.setState(server)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/ready", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireReadinessResponse(t *testing.T, recorder *httptest.ResponseRecorder) readinessResponse {
+	var rsp readinessResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	return rsp
+}
@@ -1,54 +1,243 @@
 package api
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"sync/atomic"
 
 	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/fx"
+	"github.com/aronreisx/bubblebank/metrics"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/tracing"
+	"github.com/aronreisx/bubblebank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // Server serves HTTP requests for banking service
 type Server struct {
-	store   db.Store
-	router  *gin.Engine
-	isReady bool
+	store      db.Store
+	tokenMaker token.Maker
+	router     *gin.Engine
+	httpServer *http.Server
+	// converter converts cross-currency transfers, nil when no rate source is configured
+	converter *fx.Converter
+	metrics   *metrics.Metrics
+	logger    *slog.Logger
+	// cashLimitRates convert the cash limits of the config into each currency
+	cashLimitRates map[string]*big.Rat
+	config         util.Config
+	cursorKey      []byte
+	// readinessChecks are run by the readiness probe after the database check
+	readinessChecks []readinessCheck
+	readiness       atomic.Int32
+}
+
+// ServerOption configures optional behavior of a Server
+type ServerOption func(*Server)
+
+// WithMetrics makes the server record its metrics in m, so that they are exposed
+// along with metrics recorded outside the server. By default the server has its own.
+func WithMetrics(m *metrics.Metrics) ServerOption {
+	return func(server *Server) {
+		server.metrics = m
+	}
+}
+
+// WithLogger makes the server log requests with logger instead of the default logger
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(server *Server) {
+		server.logger = logger
+	}
 }
 
 // NewServer creates a new HTTP server and setup routing.
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
-	router := gin.Default()
+func NewServer(config util.Config, store db.Store, opts ...ServerOption) (*Server, error) {
+	tokenMaker, err := token.NewMaker(config.TokenType, config.TokenSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	rates, err := fx.NewProvider(config.FXRateSource, config.FXRateFile, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rate provider: %w", err)
+	}
+
+	rounding, err := fx.ParseRoundingMode(config.FXRoundingMode)
+	if err != nil {
+		return nil, err
+	}
+
+	cashLimitRates, err := parseCashLimitRates(config.CashLimitRates)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		cursorKey:      newCursorKey(config.TokenSigningKey),
+		cashLimitRates: cashLimitRates,
+	}
+	if rates != nil {
+		server.converter = fx.NewConverter(rates, rounding)
+	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+	if server.metrics == nil {
+		server.metrics = metrics.New()
+	}
+	if server.logger == nil {
+		server.logger = slog.Default()
+	}
+
+	router := gin.New()
+	// Let handlers pass the gin context to the store, so that queries see the
+	// span and the logger of the request
+	router.ContextWithFallback = true
+	// Start a span for every request, continuing the trace of the caller if any
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	// Recover from panics within the logging middleware, so that they are logged as 500s
+	router.Use(loggingMiddleware(server.logger), gin.Recovery())
+	router.Use(metricsMiddleware(server.metrics))
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := v.RegisterValidation("currency", validCurrency); err != nil {
+			return nil, fmt.Errorf("cannot register currency validator: %w", err)
+		}
+	}
 
 	// Set trusted proxies to nil to not trust any proxy
 	if err := router.SetTrustedProxies(nil); err != nil {
-		log.Printf("Warning: Failed to set trusted proxies: %v", err)
+		server.logger.Warn("Failed to set trusted proxies", slog.Any("error", err))
 	}
 
 	// Add health and readiness endpoints
 	router.GET("/health", server.healthCheck)
 	router.GET("/ready", server.readinessCheck)
+	router.GET("/metrics", gin.WrapH(server.metrics.Handler()))
+
+	// Add public API endpoints
+	router.POST("/users", server.createUser)
+	router.POST("/users/login", server.loginUser)
+	router.POST("/tokens/renew_access", server.renewAccessToken)
+
+	// Add API endpoints that require an access token
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+
+	authRoutes.GET("/users/:username", server.getUser)
+	authRoutes.POST("/sessions/:id/revoke", server.revokeSession)
+
+	authRoutes.POST("/accounts", server.createAccount)
+	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.GET("/accounts", server.listAccounts)
+	authRoutes.PATCH("/accounts/:id/status", server.updateAccountStatus)
+	authRoutes.GET("/accounts/:id/entries", server.listEntries)
+	authRoutes.GET("/accounts/:id/transfers", server.listTransfers)
+	// Deposits create money, only bank staff can take in cash
+	authRoutes.POST("/accounts/:id/deposits", roleMiddleware(util.BankerRole, util.AdminRole), server.createDeposit)
+	authRoutes.POST("/accounts/:id/withdrawals", server.createWithdrawal)
+
+	authRoutes.POST("/transfers", server.createTransfer)
+	authRoutes.GET("/transfers/:id", server.getTransfer)
+	authRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
 
-	// Add API endpoints
-	router.POST("/accounts", server.createAccount)
-	router.GET("/accounts/:id", server.getAccount)
-	router.GET("/accounts", server.listAccounts)
+	// Add staff endpoints, bankers and admins can look up any account
+	staffRoutes := router.Group("/admin").Use(
+		authMiddleware(server.tokenMaker),
+		roleMiddleware(util.BankerRole, util.AdminRole),
+	)
+
+	staffRoutes.GET("/accounts", server.adminListAccounts)
+	staffRoutes.GET("/accounts/:id", server.adminGetAccount)
+	staffRoutes.GET("/accounts/:id/entries", server.adminListEntries)
+	staffRoutes.PATCH("/accounts/:id/status", server.adminUpdateAccountStatus)
+
+	// Add endpoints reserved to admins
+	adminRoutes := router.Group("/admin").Use(
+		authMiddleware(server.tokenMaker),
+		roleMiddleware(util.AdminRole),
+	)
+
+	adminRoutes.PATCH("/users/:username/role", server.updateUserRole)
 
 	server.router = router
-	return server
+	server.httpServer = &http.Server{
+		Handler:           router,
+		ReadHeaderTimeout: config.ServerReadTimeout,
+		ReadTimeout:       config.ServerReadTimeout,
+		WriteTimeout:      config.ServerWriteTimeout,
+		IdleTimeout:       config.ServerIdleTimeout,
+		ErrorLog:          slog.NewLogLogger(server.logger.Handler(), slog.LevelWarn),
+	}
+	return server, nil
 }
 
-// Start runs the HTTP server on a specific address.
+// Start runs the HTTP server on a specific address until it is shut down.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", address, err)
+	}
+	return server.Serve(listener)
+}
+
+// Serve accepts connections on listener until the server is shut down.
+// It returns nil once Shutdown is called.
+func (server *Server) Serve(listener net.Listener) error {
+	err := server.httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to complete.
+// Connections still open when ctx is done are closed, cutting their requests short.
+func (server *Server) Shutdown(ctx context.Context) error {
+	err := server.httpServer.Shutdown(ctx)
+	if err != nil {
+		return errors.Join(err, server.httpServer.Close())
+	}
+	return nil
 }
 
 func errorResponse(err error) gin.H {
 	return gin.H{"error": err.Error()}
 }
 
-// SetReady marks the server as ready, typically called after migrations complete
-func (server *Server) SetReady() {
-	server.isReady = true
+// errorStatus maps an error returned by the store to an HTTP status code
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrSameAccount), errors.Is(err, db.ErrInvalidAmount):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrUniqueViolation), errors.Is(err, db.ErrForeignKeyViolation),
+		errors.Is(err, db.ErrTransferAlreadyReversed), errors.Is(err, db.ErrReverseReversal),
+		errors.Is(err, db.ErrAccountNotActive), errors.Is(err, db.ErrInvalidStatusTransition),
+		errors.Is(err, db.ErrAccountNotEmpty), errors.Is(err, ErrIdempotencyKeyTakenOver):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrWithdrawalLimitExceeded),
+		errors.Is(err, db.ErrDailyDepositLimitExceeded),
+		errors.Is(err, db.ErrRefundExceedsRemainder):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, db.ErrSerializationFailure), errors.Is(err, db.ErrDeadlockDetected):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 // healthCheck handles the GET /health endpoint
@@ -57,20 +246,3 @@ func (server *Server) healthCheck(ctx *gin.Context) {
 		"status": "up",
 	})
 }
-
-// readinessCheck handles the GET /ready endpoint
-// This is used by Kubernetes/Docker to determine if the application is ready to receive traffic
-func (server *Server) readinessCheck(ctx *gin.Context) {
-	if server.isReady {
-		ctx.JSON(200, gin.H{
-			"status":     "ready",
-			"migrations": "complete",
-		})
-		return
-	}
-
-	ctx.JSON(503, gin.H{
-		"status":     "not ready",
-		"migrations": "pending",
-	})
-}
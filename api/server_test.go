@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTimeouts(t *testing.T) {
+	config := util.Config{
+		TokenType:          token.TypePaseto,
+		TokenSigningKey:    util.RandomString(32),
+		ServerReadTimeout:  5 * time.Second,
+		ServerWriteTimeout: 10 * time.Second,
+		ServerIdleTimeout:  time.Minute,
+	}
+
+	server, err := NewServer(config, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 5*time.Second, server.httpServer.ReadHeaderTimeout)
+	require.Equal(t, 5*time.Second, server.httpServer.ReadTimeout)
+	require.Equal(t, 10*time.Second, server.httpServer.WriteTimeout)
+	require.Equal(t, time.Minute, server.httpServer.IdleTimeout)
+}
+
+func TestServerShutdown(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	server.router.GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		<-finish
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	requestURL := "http://" + listener.Addr().String() + "/slow"
+	responseErr := make(chan error, 1)
+	go func() {
+		rsp, err := http.Get(requestURL) // #nosec G107 -- local test server
+		if err == nil {
+			_, _ = io.Copy(io.Discard, rsp.Body)
+			_ = rsp.Body.Close()
+			if rsp.StatusCode != http.StatusOK {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+		responseErr <- err
+	}()
+	<-started
+
+	// The in-flight request completes before Shutdown returns
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- server.Shutdown(context.Background())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(finish)
+
+	require.NoError(t, <-responseErr)
+	require.NoError(t, <-shutdownErr)
+	require.NoError(t, <-serveErr)
+}
+
+func TestServerShutdownGracePeriod(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	server.router.GET("/stuck", func(ctx *gin.Context) {
+		close(started)
+		<-ctx.Request.Context().Done()
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	requestURL := "http://" + listener.Addr().String() + "/stuck"
+	go func() {
+		rsp, err := http.Get(requestURL) // #nosec G107 -- local test server
+		if err == nil {
+			_ = rsp.Body.Close()
+		}
+	}()
+	<-started
+
+	// Requests still running after the grace period are cut short
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/aronreisx/bubblebank/db/mock"
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/token"
+	"github.com/aronreisx/bubblebank/util"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	validSession := func(refreshToken string, payload *token.Payload) db.Session {
+		return db.Session{
+			ID:           payload.ID,
+			Username:     payload.Username,
+			RefreshToken: refreshToken,
+			ExpiresAt:    payload.ExpiredAt,
+		}
+	}
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name     string
+		duration time.Duration
+		// purpose of the token sent for renewal, defaults to token.PurposeRefresh
+		purpose       string
+		body          func(refreshToken string) gin.H
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(validSession(refreshToken, payload), nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(db.User{Username: payload.Username, Role: util.BankerRole}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp renewAccessTokenResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotZero(t, rsp.AccessTokenExpiresAt)
+			},
+		},
+		{
+			name:     "GetUserError",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(validSession(refreshToken, payload), nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:     "MissingToken",
+			duration: time.Hour,
+			body: func(_ string) gin.H {
+				return gin.H{}
+			},
+			buildStubs: func(store *mockdb.MockStore, _ string, _ *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InvalidToken",
+			duration: time.Hour,
+			body: func(_ string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			buildStubs: func(store *mockdb.MockStore, _ string, _ *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "ExpiredToken",
+			duration: -time.Minute,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, _ string, _ *token.Payload) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "SessionNotFound",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, _ string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(db.Session{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "BlockedSession",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := validSession(refreshToken, payload)
+				session.IsBlocked = true
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "IncorrectSessionUser",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := validSession(refreshToken, payload)
+				session.Username = "other_user"
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "AccessToken",
+			duration: time.Hour,
+			purpose:  token.PurposeAccess,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "MismatchedSessionToken",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := validSession(refreshToken, payload)
+				session.RefreshToken = "other_token"
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "ExpiredSession",
+			duration: time.Hour,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := validSession(refreshToken, payload)
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			purpose := tc.purpose
+			if purpose == "" {
+				purpose = token.PurposeRefresh
+			}
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(username, util.DepositorRole, purpose, tc.duration)
+			require.NoError(t, err)
+
+			tc.buildStubs(store, refreshToken, payload)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(tc.body(refreshToken))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
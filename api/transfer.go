@@ -0,0 +1,345 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/fx"
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// transferResponse is the result of a transfer as seen from the account the money
+// left. The other account is only known by its ID, its owner and balance are private.
+type transferResponse struct {
+	Transfer    db.Transfer     `json:"transfer"`
+	FromAccount accountResponse `json:"from_account"`
+	FromEntry   db.Entry        `json:"from_entry"`
+}
+
+func newTransferResponse(result db.TransferTxResult) transferResponse {
+	return transferResponse{
+		Transfer:    result.Transfer,
+		FromAccount: newAccountResponse(result.FromAccount),
+		FromEntry:   result.FromEntry,
+	}
+}
+
+type transferRequest struct {
+	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        int64  `json:"amount" binding:"required_without=DecimalAmount,excluded_with=DecimalAmount,omitempty,gt=0"`
+	Currency      string `json:"currency" binding:"required,currency"`
+	// DecimalAmount is the amount in the currency's major unit, such as "12.34", given instead of Amount
+	DecimalAmount string `json:"decimal_amount" binding:"max=32"`
+}
+
+func (server *Server) createTransfer(ctx *gin.Context) {
+	var req transferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	amount, err := requestAmount(req.Currency, req.Amount, req.DecimalAmount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	req.Amount = amount
+
+	idempotent, ok := server.startIdempotentRequest(ctx, req)
+	if !ok {
+		return
+	}
+	defer idempotent.release(ctx)
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	toAccount, err := server.store.GetAccount(ctx, req.ToAccountID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	// Money only enters and leaves settlement accounts through deposits and withdrawals
+	if toAccount.Owner == db.SystemAccountOwner {
+		err := errors.New("cannot transfer to a settlement account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+	}
+	if toAccount.Currency != req.Currency {
+		if !server.convertTransfer(ctx, &arg, fromAccount, toAccount) {
+			return
+		}
+	}
+	if idempotent != nil {
+		arg.AfterTransfer = func(q db.Querier, result db.TransferTxResult) error {
+			return idempotent.complete(ctx, q, http.StatusOK, newTransferResponse(result))
+		}
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.metrics.ObserveTransfer(fromAccount.Currency, "transfer", result.Transfer.Amount)
+	ctx.JSON(http.StatusOK, newTransferResponse(result))
+}
+
+type getTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getTransfer returns a transfer to a user owning either of its accounts
+func (server *Server) getTransfer(ctx *gin.Context) {
+	var req getTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	for _, accountID := range []int64{transfer.FromAccountID, transfer.ToAccountID} {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(errorStatus(err), errorResponse(err))
+			return
+		}
+
+		if account.Owner == authPayload.Username {
+			ctx.JSON(http.StatusOK, transfer)
+			return
+		}
+	}
+
+	err = errors.New("transfer doesn't involve an account of the authenticated user")
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+}
+
+type reverseTransferRequest struct {
+	// Amount defaults to what is left to refund
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// reverseTransfer moves the money of a transfer back to its source account, in
+// full or in part. The refund is a transfer of its own, linked to the original.
+// Only the owner of the destination account, or staff, can give money back.
+// The response holds the refunding account, the destination of the original transfer.
+func (server *Server) reverseTransfer(ctx *gin.Context) {
+	var uri getTransferRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The body is optional, an empty one refunds the whole remainder
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	idempotent, ok := server.startIdempotentRequest(ctx, req)
+	if !ok {
+		return
+	}
+	defer idempotent.release(ctx)
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if !slices.Contains([]string{util.BankerRole, util.AdminRole}, authPayload.Role) {
+		toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+		if err != nil {
+			ctx.JSON(errorStatus(err), errorResponse(err))
+			return
+		}
+
+		if toAccount.Owner != authPayload.Username {
+			err := errors.New("transfer wasn't received by an account of the authenticated user")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.Amount,
+	}
+	if idempotent != nil {
+		arg.AfterReverse = func(q db.Querier, result db.TransferTxResult) error {
+			return idempotent.complete(ctx, q, http.StatusOK, newTransferResponse(result))
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.metrics.ObserveTransfer(result.FromAccount.Currency, "reversal", result.Transfer.Amount)
+	ctx.JSON(http.StatusOK, newTransferResponse(result))
+}
+
+type listTransfersRequest struct {
+	From           time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty,gtfield=From"`
+	Direction      string    `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	CounterpartyID int64     `form:"counterparty_id" binding:"omitempty,min=1"`
+	// MinAmount and MaxAmount are in minor units of the account's currency,
+	// matching the amount it sent or received
+	MinAmount int64 `form:"min_amount" binding:"omitempty,gt=0"`
+	MaxAmount int64 `form:"max_amount" binding:"omitempty,gt=0,gtefield=MinAmount"`
+	pageRequest
+}
+
+// listTransfers lists the incoming, outgoing or all transfers of an account
+// owned by the authenticated user
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	authPayload := authorizationPayload(ctx)
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	scope := cursorScope(ctx.Request.URL.Path)
+	page, err := newPage(server.cursorKey, scope, req.pageRequest)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListTransfersParams{
+		AccountID:       account.ID,
+		Direction:       pgtype.Text{String: req.Direction, Valid: req.Direction != ""},
+		CounterpartyID:  pgtype.Int8{Int64: req.CounterpartyID, Valid: req.CounterpartyID > 0},
+		MinAmount:       pgtype.Int8{Int64: req.MinAmount, Valid: req.MinAmount > 0},
+		MaxAmount:       pgtype.Int8{Int64: req.MaxAmount, Valid: req.MaxAmount > 0},
+		FromTime:        pgtype.Timestamptz{Time: req.From, Valid: !req.From.IsZero()},
+		ToTime:          pgtype.Timestamptz{Time: req.To, Valid: !req.To.IsZero()},
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit,
+		Offset:          page.Offset,
+	}
+
+	transfers, err := server.store.ListTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return
+	}
+
+	writePage(ctx, server.cursorKey, scope, req.pageRequest, transfers, transferCursor)
+}
+
+// transferCursor returns the pagination key of a transfer
+func transferCursor(transfer db.Transfer) cursor {
+	return cursor{CreatedAt: transfer.CreatedAt, ID: transfer.ID}
+}
+
+// convertTransfer sets the amount credited to the destination account of a
+// cross-currency transfer, along with the exchange rate it was converted with.
+// It writes the error response itself and reports whether the handler may go on.
+func (server *Server) convertTransfer(ctx *gin.Context, arg *db.TransferTxParams, fromAccount, toAccount db.Account) bool {
+	if server.converter == nil {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", toAccount.ID, toAccount.Currency, fromAccount.Currency)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return false
+	}
+
+	conversion, err := server.converter.Convert(ctx, arg.Amount, fromAccount.Currency, toAccount.Currency, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, fx.ErrRateNotFound):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		case errors.Is(err, fx.ErrAmountTooSmall), errors.Is(err, fx.ErrAmountTooLarge):
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return false
+	}
+
+	arg.Rate, err = fx.RatToNumeric(conversion.Rate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	arg.ToAmount = conversion.ToAmount
+	arg.RateAt = pgtype.Timestamptz{Time: conversion.RateAt, Valid: true}
+	return true
+}
+
+// validAccount checks that the account exists and holds the given currency.
+// It writes the error response itself and reports whether the handler may go on.
+func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		ctx.JSON(errorStatus(err), errorResponse(err))
+		return account, false
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return account, false
+	}
+
+	return account, true
+}
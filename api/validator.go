@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/aronreisx/bubblebank/currency"
+	"github.com/go-playground/validator/v10"
+)
+
+// validCurrency checks that a field holds a supported ISO 4217 currency code
+var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if code, ok := fieldLevel.Field().Interface().(string); ok {
+		return currency.IsSupported(code)
+	}
+	return false
+}
+
+// requestAmount returns the amount of a request in minor units of currency code.
+// Requests give either amount, in minor units, or decimalAmount, such as "12.34".
+func requestAmount(code string, amount int64, decimalAmount string) (int64, error) {
+	if decimalAmount == "" {
+		return amount, nil
+	}
+
+	c, err := currency.Get(code)
+	if err != nil {
+		return 0, err
+	}
+
+	amount, err = c.Parse(decimalAmount)
+	if err != nil {
+		return 0, err
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("%w %q: must be positive", currency.ErrInvalidAmount, decimalAmount)
+	}
+
+	return amount, nil
+}
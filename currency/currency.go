@@ -0,0 +1,134 @@
+// Package currency holds the registry of ISO 4217 currencies supported by the bank.
+// Amounts are always stored as integers in the currency's minor unit, for example
+// cents for USD, and are converted to and from decimal strings with Format and Parse.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedCurrency is returned when a currency code is not in the registry
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrInvalidAmount is returned when a decimal amount cannot be parsed for a currency
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// Currency is an ISO 4217 currency
+type Currency struct {
+	Code string
+	// Exponent is the number of digits of the minor unit, e.g. 2 for USD and 0 for JPY
+	Exponent int
+}
+
+// registry lists the supported currencies.
+// Keep it in sync with the rows seeded in the currencies table.
+var registry = map[string]Currency{
+	"CAD": {Code: "CAD", Exponent: 2},
+	"EUR": {Code: "EUR", Exponent: 2},
+	"GBP": {Code: "GBP", Exponent: 2},
+	"JPY": {Code: "JPY", Exponent: 0},
+	"KWD": {Code: "KWD", Exponent: 3},
+	"USD": {Code: "USD", Exponent: 2},
+}
+
+// Get returns the currency with the given ISO 4217 code
+func Get(code string) (Currency, error) {
+	c, ok := registry[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w %q", ErrUnsupportedCurrency, code)
+	}
+	return c, nil
+}
+
+// IsSupported reports whether code is a supported ISO 4217 code
+func IsSupported(code string) bool {
+	_, ok := registry[code]
+	return ok
+}
+
+// Codes returns the codes of all supported currencies, sorted
+func Codes() []string {
+	codes := make([]string, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// MinorUnits converts an amount of whole units of the currency, such as dollars,
+// into minor units. Amounts too large to be represented are capped to math.MaxInt64.
+func (c Currency) MinorUnits(units int64) int64 {
+	amount := units
+	for range c.Exponent {
+		if amount > math.MaxInt64/10 {
+			return math.MaxInt64
+		}
+		amount *= 10
+	}
+	return amount
+}
+
+// Format returns amount, given in minor units, as a decimal string such as "-12.34"
+func (c Currency) Format(amount int64) string {
+	if c.Exponent == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	// Work on the unsigned value so that math.MinInt64 does not overflow
+	abs := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	digits := strconv.FormatUint(abs, 10)
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+
+	split := len(digits) - c.Exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// Parse converts a decimal string such as "12.34" into an amount in minor units.
+// It rejects amounts with more decimals than the currency's exponent.
+func (c Currency) Parse(s string) (int64, error) {
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		sign, whole = whole[:1], whole[1:]
+	}
+
+	if whole == "" || !isDigits(whole) || (hasFraction && (fraction == "" || !isDigits(fraction))) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+
+	if len(fraction) > c.Exponent {
+		return 0, fmt.Errorf("%w %q: %s has %d decimals", ErrInvalidAmount, s, c.Code, c.Exponent)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", c.Exponent-len(fraction))
+	amount, err := strconv.ParseInt(sign+digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: out of range", ErrInvalidAmount, s)
+	}
+
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
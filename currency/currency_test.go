@@ -0,0 +1,127 @@
+package currency
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	c, err := Get("JPY")
+	require.NoError(t, err)
+	require.Equal(t, Currency{Code: "JPY", Exponent: 0}, c)
+
+	_, err = Get("XXX")
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	require.True(t, IsSupported("USD"))
+	require.False(t, IsSupported("usd"))
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	require.Len(t, codes, len(registry))
+	require.IsIncreasing(t, codes)
+	for _, code := range codes {
+		require.True(t, IsSupported(code))
+	}
+}
+
+func TestFormat(t *testing.T) {
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		code     string
+		amount   int64
+		expected string
+	}{
+		{"USD", 0, "0.00"},
+		{"USD", 5, "0.05"},
+		{"USD", 1234, "12.34"},
+		{"USD", -1234, "-12.34"},
+		{"JPY", 1234, "1234"},
+		{"JPY", -5, "-5"},
+		{"KWD", 1234, "1.234"},
+		{"KWD", 12, "0.012"},
+		{"USD", math.MinInt64, "-92233720368547758.08"},
+	}
+
+	for _, tc := range testCases {
+		c, err := Get(tc.code)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, c.Format(tc.amount))
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		code     string
+		units    int64
+		expected int64
+	}{
+		{"USD", 100, 10000},
+		{"JPY", 100, 100},
+		{"KWD", 100, 100000},
+		{"USD", 0, 0},
+		{"KWD", math.MaxInt64 / 100, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		c, err := Get(tc.code)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, c.MinorUnits(tc.units))
+	}
+}
+
+func TestParse(t *testing.T) {
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		code     string
+		input    string
+		expected int64
+		valid    bool
+	}{
+		{"USD", "12.34", 1234, true},
+		{"USD", "12.3", 1230, true},
+		{"USD", "12", 1200, true},
+		{"USD", "-0.05", -5, true},
+		{"USD", "+1.00", 100, true},
+		{"JPY", "1234", 1234, true},
+		{"KWD", "1.234", 1234, true},
+		{"USD", "12.345", 0, false},
+		{"JPY", "12.3", 0, false},
+		{"USD", "", 0, false},
+		{"USD", "12.", 0, false},
+		{"USD", ".5", 0, false},
+		{"USD", "1,00", 0, false},
+		{"USD", "abc", 0, false},
+		{"USD", "99999999999999999999", 0, false},
+	}
+
+	for _, tc := range testCases {
+		c, err := Get(tc.code)
+		require.NoError(t, err)
+
+		amount, err := c.Parse(tc.input)
+		if !tc.valid {
+			require.ErrorIs(t, err, ErrInvalidAmount, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, amount)
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	for _, code := range Codes() {
+		c, err := Get(code)
+		require.NoError(t, err)
+
+		for _, amount := range []int64{0, 1, -1, 987654321, math.MaxInt64} {
+			parsed, err := c.Parse(c.Format(amount))
+			require.NoError(t, err)
+			require.Equal(t, amount, parsed)
+		}
+	}
+}
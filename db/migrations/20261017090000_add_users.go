@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddUsers, downAddUsers)
+}
+
+func upAddUsers(ctx context.Context, tx *sql.Tx) error {
+	// Create users table
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "users" (
+		  "username" varchar PRIMARY KEY,
+		  "hashed_password" varchar NOT NULL,
+		  "full_name" varchar NOT NULL,
+		  "email" varchar UNIQUE NOT NULL,
+		  "password_changed_at" timestamptz NOT NULL DEFAULT ('0001-01-01 00:00:00Z'),
+		  "created_at" timestamptz NOT NULL DEFAULT (now())
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Accounts created before users existed have free-text owners. Give each owner a
+	// placeholder user, its password hash is empty so that nobody can log in as it
+	// until staff set the user up.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+		SELECT DISTINCT "owner", '', "owner", "owner" || '@placeholder.bubblebank.invalid'
+		FROM "accounts"
+		ON CONFLICT ("username") DO NOTHING;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Two accounts of an owner in the same currency hold money and history of their own,
+	// merging them is left to staff rather than done here
+	var duplicates int
+	err = tx.QueryRowContext(ctx, `
+		SELECT count(*) FROM (
+		  SELECT 1 FROM "accounts" GROUP BY "owner", "currency" HAVING count(*) > 1
+		) AS "duplicates";
+	`).Scan(&duplicates)
+	if err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		return fmt.Errorf("%d owners have several accounts in the same currency, merge them before migrating", duplicates)
+	}
+
+	// Link accounts to their owner, one account per currency
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+		ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddUsers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+		ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_owner_fkey";
+		DROP TABLE IF EXISTS users;
+	`)
+	return err
+}
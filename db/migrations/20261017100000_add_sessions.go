@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddSessions, downAddSessions)
+}
+
+func upAddSessions(ctx context.Context, tx *sql.Tx) error {
+	// Create sessions table, one row per issued refresh token
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "sessions" (
+		  "id" uuid PRIMARY KEY,
+		  "username" varchar NOT NULL,
+		  "refresh_token" varchar NOT NULL,
+		  "user_agent" varchar NOT NULL,
+		  "client_ip" varchar NOT NULL,
+		  "is_blocked" boolean NOT NULL DEFAULT false,
+		  "expires_at" timestamptz NOT NULL,
+		  "created_at" timestamptz NOT NULL DEFAULT (now())
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add foreign keys and indexes
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+		CREATE INDEX ON "sessions" ("username");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddSessions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS sessions;
+	`)
+	return err
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddUserRoles, downAddUserRoles)
+}
+
+func upAddUserRoles(ctx context.Context, tx *sql.Tx) error {
+	// Add role column, existing users become depositors
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "users" ADD COLUMN IF NOT EXISTS "role" varchar NOT NULL DEFAULT 'depositor';
+		ALTER TABLE IF EXISTS "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('depositor', 'banker', 'admin'));
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddUserRoles(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "users" DROP CONSTRAINT IF EXISTS "users_role_check";
+		ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
+	`)
+	return err
+}
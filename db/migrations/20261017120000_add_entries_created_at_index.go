@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEntriesCreatedAtIndex, downAddEntriesCreatedAtIndex)
+}
+
+func upAddEntriesCreatedAtIndex(ctx context.Context, tx *sql.Tx) error {
+	// Index account statements, which filter and sort entries by time
+	_, err := tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS "entries_account_id_created_at_idx" ON "entries" ("account_id", "created_at");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddEntriesCreatedAtIndex(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS "entries_account_id_created_at_idx";
+	`)
+	return err
+}
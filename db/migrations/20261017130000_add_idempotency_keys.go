@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddIdempotencyKeys, downAddIdempotencyKeys)
+}
+
+func upAddIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	// Create idempotency_keys table, one row per key a user sent on a mutating request
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+		  "username" varchar NOT NULL,
+		  "key" varchar NOT NULL,
+		  "request_hash" varchar NOT NULL,
+		  "status" varchar NOT NULL DEFAULT 'in_progress',
+		  "response_code" integer NOT NULL DEFAULT 0,
+		  "response_body" bytea,
+		  "locked_at" timestamptz NOT NULL DEFAULT (now()),
+		  "created_at" timestamptz NOT NULL DEFAULT (now()),
+		  PRIMARY KEY ("username", "key"),
+		  CONSTRAINT "idempotency_keys_status_check" CHECK ("status" IN ('in_progress', 'completed'))
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add foreign keys
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE IF EXISTS idempotency_keys;
+	`)
+	return err
+}
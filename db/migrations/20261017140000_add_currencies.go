@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddCurrencies, downAddCurrencies)
+}
+
+func upAddCurrencies(ctx context.Context, tx *sql.Tx) error {
+	// Create currencies reference table, exponent is the number of minor unit digits
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "currencies" (
+		  "code" varchar(3) PRIMARY KEY,
+		  "exponent" smallint NOT NULL,
+		  CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Seed the currencies supported by the currency package
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "currencies" ("code", "exponent") VALUES
+		  ('CAD', 2),
+		  ('EUR', 2),
+		  ('GBP', 2),
+		  ('JPY', 0),
+		  ('KWD', 3),
+		  ('USD', 2)
+		ON CONFLICT ("code") DO NOTHING;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add foreign keys
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "accounts_currency_fkey"
+		  FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddCurrencies(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_currency_fkey";
+		DROP TABLE IF EXISTS currencies;
+	`)
+	return err
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddFXRates, downAddFXRates)
+}
+
+func upAddFXRates(ctx context.Context, tx *sql.Tx) error {
+	// Create fx_rates table, a rate applies from effective_at until the next rate of the same pair
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "fx_rates" (
+		  "id" bigserial PRIMARY KEY,
+		  "base_currency" varchar(3) NOT NULL,
+		  "quote_currency" varchar(3) NOT NULL,
+		  "rate" numeric(24,12) NOT NULL,
+		  "effective_at" timestamptz NOT NULL,
+		  "created_at" timestamptz NOT NULL DEFAULT (now()),
+		  CONSTRAINT "fx_rates_rate_check" CHECK ("rate" > 0),
+		  CONSTRAINT "fx_rates_pair_check" CHECK ("base_currency" <> "quote_currency"),
+		  CONSTRAINT "fx_rates_pair_effective_at_key" UNIQUE ("base_currency", "quote_currency", "effective_at")
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add foreign keys
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "fx_rates" ADD FOREIGN KEY ("base_currency") REFERENCES "currencies" ("code");
+		ALTER TABLE IF EXISTS "fx_rates" ADD FOREIGN KEY ("quote_currency") REFERENCES "currencies" ("code");
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Record the amount credited and the rate used on transfers,
+	// existing transfers were all between accounts of the same currency
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "transfers" ADD COLUMN IF NOT EXISTS "to_amount" bigint;
+		UPDATE "transfers" SET "to_amount" = "amount" WHERE "to_amount" IS NULL;
+		ALTER TABLE IF EXISTS "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+		ALTER TABLE IF EXISTS "transfers" ADD COLUMN IF NOT EXISTS "rate" numeric(24,12);
+		ALTER TABLE IF EXISTS "transfers" ADD COLUMN IF NOT EXISTS "rate_at" timestamptz;
+		ALTER TABLE IF EXISTS "transfers" ADD CONSTRAINT "transfers_rate_check"
+		  CHECK (("rate" IS NULL) = ("rate_at" IS NULL));
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add comments
+	_, err = tx.ExecContext(ctx, `
+		COMMENT ON COLUMN "transfers"."amount" IS 'Must be positive, in the currency of the source account';
+		COMMENT ON COLUMN "transfers"."to_amount" IS 'Must be positive, in the currency of the destination account';
+		COMMENT ON COLUMN "transfers"."rate" IS 'Exchange rate applied, null when both accounts share a currency';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddFXRates(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "transfers" DROP CONSTRAINT IF EXISTS "transfers_rate_check";
+		ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "rate_at";
+		ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "rate";
+		ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_amount";
+		DROP TABLE IF EXISTS fx_rates;
+	`)
+	return err
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddSystemAccounts, downAddSystemAccounts)
+}
+
+func upAddSystemAccounts(ctx context.Context, tx *sql.Tx) error {
+	// Tell entries of deposits and withdrawals apart from transfer entries and let them carry a memo
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "entries" ADD COLUMN IF NOT EXISTS "kind" varchar NOT NULL DEFAULT 'transfer';
+		ALTER TABLE IF EXISTS "entries" ADD COLUMN IF NOT EXISTS "memo" varchar NOT NULL DEFAULT '';
+		ALTER TABLE IF EXISTS "entries" ADD CONSTRAINT "entries_kind_check"
+		  CHECK ("kind" IN ('transfer', 'deposit', 'withdrawal'));
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Create the system user owning the settlement accounts.
+	// Its username is not alphanumeric, so no customer can register it,
+	// and its password hash is empty, so nobody can log in as it.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+		VALUES ('_system', '', 'Bubblebank settlement', '_system@bubblebank.invalid')
+		ON CONFLICT ("username") DO NOTHING;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Create one settlement account per currency, deposits and withdrawals post their
+	// other leg there so that the balances of each currency always add up to zero.
+	// Migrations adding currencies must add their settlement account too.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "accounts" ("owner", "balance", "currency")
+		SELECT '_system', 0, "code" FROM "currencies"
+		ON CONFLICT ("owner", "currency") DO NOTHING;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add index for the daily withdrawal limit
+	_, err = tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS "entries_account_id_kind_created_at_idx" ON "entries" ("account_id", "kind", "created_at");
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddSystemAccounts(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS entries_account_id_kind_created_at_idx;
+		DELETE FROM entries WHERE account_id IN (SELECT id FROM accounts WHERE owner = '_system');
+		DELETE FROM accounts WHERE owner = '_system';
+		DELETE FROM users WHERE username = '_system';
+		ALTER TABLE IF EXISTS "entries" DROP CONSTRAINT IF EXISTS "entries_kind_check";
+		ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "memo";
+		ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "kind";
+	`)
+	return err
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEntriesTransferID, downAddEntriesTransferID)
+}
+
+func upAddEntriesTransferID(ctx context.Context, tx *sql.Tx) error {
+	// Link transfer entries to their transfer
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "entries" ADD COLUMN IF NOT EXISTS "transfer_id" bigint;
+		ALTER TABLE IF EXISTS "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+		CREATE INDEX IF NOT EXISTS "entries_transfer_id_idx" ON "entries" ("transfer_id");
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Backfill existing entries. A transfer and its two entries were written by the
+	// same transaction, so they share the transaction's timestamp.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE "entries" e
+		SET "transfer_id" = t."id"
+		FROM "transfers" t
+		WHERE e."kind" = 'transfer'
+		  AND e."transfer_id" IS NULL
+		  AND e."created_at" = t."created_at"
+		  AND (
+		    (e."account_id" = t."from_account_id" AND e."amount" = -t."amount")
+		    OR (e."account_id" = t."to_account_id" AND e."amount" = t."to_amount")
+		  );
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddEntriesTransferID(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS entries_transfer_id_idx;
+		ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "transfer_id";
+	`)
+	return err
+}
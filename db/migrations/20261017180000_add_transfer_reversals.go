@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddTransferReversals, downAddTransferReversals)
+}
+
+func upAddTransferReversals(ctx context.Context, tx *sql.Tx) error {
+	// Link compensating transfers to the transfer they reverse
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "transfers" ADD COLUMN IF NOT EXISTS "reversal_of" bigint;
+		ALTER TABLE IF EXISTS "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+		CREATE INDEX IF NOT EXISTS "transfers_reversal_of_idx" ON "transfers" ("reversal_of");
+		COMMENT ON COLUMN "transfers"."reversal_of" IS 'Transfer this one partially or fully reverses';
+	`)
+	return err
+}
+
+func downAddTransferReversals(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS transfers_reversal_of_idx;
+		ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversal_of";
+	`)
+	return err
+}
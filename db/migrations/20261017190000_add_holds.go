@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddHolds, downAddHolds)
+}
+
+func upAddHolds(ctx context.Context, tx *sql.Tx) error {
+	// Create holds table, funds reserved on an account until they are captured into a transfer,
+	// released or expire
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS "holds" (
+		  "id" bigserial PRIMARY KEY,
+		  "account_id" bigint NOT NULL,
+		  "to_account_id" bigint NOT NULL,
+		  "amount" bigint NOT NULL,
+		  "captured_amount" bigint NOT NULL DEFAULT 0,
+		  "status" varchar NOT NULL DEFAULT 'active',
+		  "transfer_id" bigint,
+		  "memo" varchar NOT NULL DEFAULT '',
+		  "expires_at" timestamptz NOT NULL,
+		  "closed_at" timestamptz,
+		  "created_at" timestamptz NOT NULL DEFAULT (now()),
+		  CONSTRAINT "holds_amount_check" CHECK ("amount" > 0 AND "captured_amount" BETWEEN 0 AND "amount"),
+		  CONSTRAINT "holds_status_check" CHECK ("status" IN ('active', 'captured', 'released', 'expired'))
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add foreign keys
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+		ALTER TABLE IF EXISTS "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+		ALTER TABLE IF EXISTS "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add indexes, the partial one serves the expiry of active holds
+	_, err = tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS "holds_account_id_idx" ON "holds" ("account_id");
+		CREATE INDEX IF NOT EXISTS "holds_active_expires_at_idx" ON "holds" ("expires_at") WHERE "status" = 'active';
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Keep the total of the active holds of each account next to its balance
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" ADD COLUMN IF NOT EXISTS "held_balance" bigint NOT NULL DEFAULT 0;
+		ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "accounts_held_balance_check" CHECK ("held_balance" >= 0);
+		COMMENT ON COLUMN "accounts"."held_balance" IS 'Sum of the active holds on the account';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddHolds(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "held_balance";
+		DROP TABLE IF EXISTS holds;
+	`)
+	return err
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddAccountStatus, downAddAccountStatus)
+}
+
+func upAddAccountStatus(ctx context.Context, tx *sql.Tx) error {
+	// Accounts are frozen and closed rather than deleted, so that their ledger history stays
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" ADD COLUMN IF NOT EXISTS "status" varchar NOT NULL DEFAULT 'active';
+		ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "accounts_status_check"
+		  CHECK ("status" IN ('active', 'frozen', 'closed'));
+	`)
+	if err != nil {
+		return err
+	}
+
+	// A user may open a new account in the currency of an account they closed
+	_, err = tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+		CREATE UNIQUE INDEX IF NOT EXISTS "accounts_owner_currency_open_key" ON "accounts" ("owner", "currency")
+		  WHERE "status" <> 'closed';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddAccountStatus(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS accounts_owner_currency_open_key;
+		ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "status";
+		ALTER TABLE IF EXISTS "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+	`)
+	return err
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEntriesBalanceAfter, downAddEntriesBalanceAfter)
+}
+
+func upAddEntriesBalanceAfter(ctx context.Context, tx *sql.Tx) error {
+	// Record the balance of the account after each entry, so that statements
+	// read a page of entries rather than the whole history of the account
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE IF EXISTS "entries" ADD COLUMN IF NOT EXISTS "balance_after" bigint;
+		COMMENT ON COLUMN "entries"."balance_after" IS 'Balance of the account right after the entry was posted';
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Derive the balance after the existing entries from the current balance,
+	// which also covers accounts opened with a non-zero balance
+	_, err = tx.ExecContext(ctx, `
+		UPDATE "entries" SET "balance_after" = "statement"."balance_after"
+		FROM (
+		  SELECT e."id",
+		    a."balance" - SUM(e."amount") OVER (PARTITION BY e."account_id")
+		      + SUM(e."amount") OVER (PARTITION BY e."account_id" ORDER BY e."created_at", e."id") AS "balance_after"
+		  FROM "entries" e
+		    JOIN "accounts" a ON a."id" = e."account_id"
+		) AS "statement"
+		WHERE "entries"."id" = "statement"."id";
+		ALTER TABLE IF EXISTS "entries" ALTER COLUMN "balance_after" SET NOT NULL;
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Let statement pages seek to their keyset cursor, which includes the entry ID
+	_, err = tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS "entries_account_id_created_at_id_idx" ON "entries" ("account_id", "created_at", "id");
+		DROP INDEX IF EXISTS "entries_account_id_created_at_idx";
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func downAddEntriesBalanceAfter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS "entries_account_id_created_at_idx" ON "entries" ("account_id", "created_at");
+		DROP INDEX IF EXISTS "entries_account_id_created_at_id_idx";
+		ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "balance_after";
+	`)
+	return err
+}
@@ -13,11 +13,56 @@ FROM accounts
 WHERE id = $1
 LIMIT 1 FOR NO KEY
 UPDATE;
+-- name: GetSystemAccount :one
+-- Settlement account of a currency, owned by the system user
+SELECT *
+FROM accounts
+WHERE owner = '_system'
+    AND currency = $1
+LIMIT 1;
 -- name: ListAccounts :many
+-- Pages are either read with OFFSET or, when the cursor is set,
+-- from the (created_at, id) key of the last row of the previous page.
+SELECT *
+FROM accounts
+WHERE owner = sqlc.arg(owner)
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) > (
+            sqlc.narg(cursor_created_at),
+            sqlc.narg(cursor_id)::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+-- name: ListAllAccounts :many
+SELECT *
+FROM accounts
+WHERE (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) > (
+            sqlc.narg(cursor_created_at),
+            sqlc.narg(cursor_id)::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+-- name: SearchAccountsByOwner :many
 SELECT *
 FROM accounts
-ORDER BY id
-LIMIT $1 OFFSET $2;
+WHERE owner LIKE sqlc.arg(owner_prefix)::varchar || '%'
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) > (
+            sqlc.narg(cursor_created_at),
+            sqlc.narg(cursor_id)::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 -- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
@@ -28,6 +73,13 @@ UPDATE accounts
 SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
@@ -1,6 +1,13 @@
 -- name: CreateEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES ($1, $2)
+INSERT INTO entries (
+        account_id,
+        amount,
+        kind,
+        memo,
+        transfer_id,
+        balance_after
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 -- name: GetEntry :one
 SELECT *
@@ -12,4 +19,76 @@ SELECT *
 FROM entries
 WHERE account_id = $1
 ORDER BY id
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+LIMIT $2 OFFSET $3;
+-- name: ListAccountEntries :many
+-- Entries of an account with the balance right after each entry
+SELECT id,
+    account_id,
+    amount,
+    created_at,
+    kind,
+    memo,
+    transfer_id,
+    balance_after AS running_balance
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+    AND (
+        sqlc.narg(from_time)::timestamptz IS NULL
+        OR created_at >= sqlc.narg(from_time)
+    )
+    AND (
+        sqlc.narg(to_time)::timestamptz IS NULL
+        OR created_at < sqlc.narg(to_time)
+    )
+    AND (
+        sqlc.narg(direction)::varchar IS NULL
+        OR (
+            sqlc.narg(direction) = 'credit'
+            AND amount > 0
+        )
+        OR (
+            sqlc.narg(direction) = 'debit'
+            AND amount < 0
+        )
+    )
+    AND (
+        sqlc.narg(min_amount)::bigint IS NULL
+        OR abs(amount) >= sqlc.narg(min_amount)
+    )
+    AND (
+        sqlc.narg(max_amount)::bigint IS NULL
+        OR abs(amount) <= sqlc.narg(max_amount)
+    )
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (
+            sqlc.arg(sort_desc)::boolean
+            AND (created_at, id) < (
+                sqlc.narg(cursor_created_at),
+                sqlc.narg(cursor_id)::bigint
+            )
+        )
+        OR (
+            NOT sqlc.arg(sort_desc)::boolean
+            AND (created_at, id) > (
+                sqlc.narg(cursor_created_at),
+                sqlc.narg(cursor_id)::bigint
+            )
+        )
+    )
+ORDER BY CASE
+        WHEN sqlc.arg(sort_desc)::boolean THEN created_at
+    END DESC,
+    CASE
+        WHEN sqlc.arg(sort_desc)::boolean THEN id
+    END DESC,
+    created_at,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+-- name: SumAccountEntriesSince :one
+-- Total amount of the entries of a kind posted on an account since a time
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1
+    AND kind = $2
+    AND created_at >= sqlc.arg(since);
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- name: CreateFXRate :one
+INSERT INTO fx_rates (
+        base_currency,
+        quote_currency,
+        rate,
+        effective_at
+    )
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+-- name: GetFXRate :one
+SELECT *
+FROM fx_rates
+WHERE base_currency = $1
+    AND quote_currency = $2
+    AND effective_at <= sqlc.arg(at)
+ORDER BY effective_at DESC
+LIMIT 1;
\ No newline at end of file
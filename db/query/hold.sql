@@ -0,0 +1,54 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+        account_id,
+        to_account_id,
+        amount,
+        memo,
+        expires_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+-- name: GetHold :one
+SELECT *
+FROM holds
+WHERE id = $1
+LIMIT 1;
+-- name: GetHoldForUpdate :one
+SELECT *
+FROM holds
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
+-- name: CloseHold :one
+UPDATE holds
+SET status = $2,
+    captured_amount = $3,
+    transfer_id = $4,
+    closed_at = now()
+WHERE id = $1
+RETURNING *;
+-- name: ExpireAccountHolds :one
+-- Expires the active holds of an account past their expiry and frees their funds
+WITH expired AS (
+    UPDATE holds
+    SET status = 'expired',
+        closed_at = now()
+    WHERE holds.account_id = $1
+        AND status = 'active'
+        AND expires_at <= now()
+    RETURNING amount
+)
+UPDATE accounts
+SET held_balance = held_balance - (
+        SELECT COALESCE(SUM(amount), 0)
+        FROM expired
+    )
+WHERE accounts.id = $1
+RETURNING *;
+-- name: ListAccountsWithExpiredHolds :many
+-- Accounts with active holds past their expiry, in the order accounts are locked in
+SELECT DISTINCT account_id
+FROM holds
+WHERE status = 'active'
+    AND expires_at <= now()
+ORDER BY account_id;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- name: CreateIdempotencyKey :one
+-- Claims a key for a new request. A key left in progress since before
+-- stale_before by a request that never finished is taken over, as long as
+-- the request is the same. No row is returned when the key is already taken.
+INSERT INTO idempotency_keys (username, key, request_hash)
+VALUES ($1, $2, $3) ON CONFLICT (username, key) DO
+UPDATE
+SET locked_at = now()
+WHERE idempotency_keys.status = 'in_progress'
+    AND idempotency_keys.request_hash = EXCLUDED.request_hash
+    AND idempotency_keys.locked_at < sqlc.arg(stale_before)
+RETURNING *;
+-- name: GetIdempotencyKey :one
+SELECT *
+FROM idempotency_keys
+WHERE username = $1
+    AND key = $2
+LIMIT 1;
+-- name: CompleteIdempotencyKey :one
+-- Stores the response of the request holding the key since locked_at.
+-- No row is returned when a retry took the key over in the meantime.
+UPDATE idempotency_keys
+SET status = 'completed',
+    response_code = $3,
+    response_body = $4
+WHERE username = $1
+    AND key = $2
+    AND status = 'in_progress'
+    AND locked_at = sqlc.arg(locked_at)
+RETURNING *;
+-- name: DeleteIdempotencyKey :exec
+-- Frees the key held since locked_at, leaving a key taken over by a retry alone
+DELETE FROM idempotency_keys
+WHERE username = $1
+    AND key = $2
+    AND status = 'in_progress'
+    AND locked_at = sqlc.arg(locked_at);
\ No newline at end of file
@@ -0,0 +1,44 @@
+-- name: ListAccountBalances :many
+-- Balance of a batch of accounts next to the sum of their entries
+SELECT a.id,
+    a.owner,
+    a.currency,
+    a.balance,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+    LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg('limit');
+-- name: ListTransferEntryCounts :many
+-- Entries of a batch of transfers, counting those matching each leg
+SELECT t.id,
+    COUNT(e.id) AS entry_count,
+    COUNT(e.id) FILTER (
+        WHERE e.account_id = t.from_account_id
+            AND e.amount = - t.amount
+    ) AS debit_count,
+    COUNT(e.id) FILTER (
+        WHERE e.account_id = t.to_account_id
+            AND e.amount = t.to_amount
+    ) AS credit_count
+FROM transfers t
+    LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.id > sqlc.arg(after_id)
+GROUP BY t.id
+ORDER BY t.id
+LIMIT sqlc.arg('limit');
+-- name: ListOrphanedEntries :many
+-- Transfer entries that are not linked to any transfer
+SELECT *
+FROM entries
+WHERE kind = 'transfer'
+    AND transfer_id IS NULL
+    AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1;
\ No newline at end of file
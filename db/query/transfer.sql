@@ -2,19 +2,91 @@
 INSERT INTO transfers (
         from_account_id,
         to_account_id,
-        amount
+        amount,
+        to_amount,
+        rate,
+        rate_at,
+        reversal_of
     )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 -- name: GetTransfer :one
 SELECT *
 FROM transfers
 WHERE id = $1
 LIMIT 1;
+-- name: GetTransferForUpdate :one
+SELECT *
+FROM transfers
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE;
+-- name: SumTransferReversals :one
+-- Amounts already moved back by the reversals of a transfer
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount,
+    COALESCE(SUM(to_amount), 0)::bigint AS to_amount
+FROM transfers
+WHERE reversal_of = $1;
 -- name: ListTransfers :many
+-- Amount filters compare the amount in the currency of the listed account,
+-- to_amount when it received the transfer
 SELECT *
 FROM transfers
-WHERE from_account_id = $1
-    OR to_account_id = $2
-ORDER BY id
-LIMIT $3 OFFSET $4;
\ No newline at end of file
+WHERE (
+        from_account_id = sqlc.arg(account_id)
+        OR to_account_id = sqlc.arg(account_id)
+    )
+    AND (
+        sqlc.narg(direction)::varchar IS NULL
+        OR (
+            sqlc.narg(direction) = 'outgoing'
+            AND from_account_id = sqlc.arg(account_id)
+        )
+        OR (
+            sqlc.narg(direction) = 'incoming'
+            AND to_account_id = sqlc.arg(account_id)
+        )
+    )
+    AND (
+        sqlc.narg(counterparty_id)::bigint IS NULL
+        OR (
+            from_account_id = sqlc.arg(account_id)
+            AND to_account_id = sqlc.narg(counterparty_id)
+        )
+        OR (
+            to_account_id = sqlc.arg(account_id)
+            AND from_account_id = sqlc.narg(counterparty_id)
+        )
+    )
+    AND (
+        sqlc.narg(min_amount)::bigint IS NULL
+        OR CASE
+            WHEN from_account_id = sqlc.arg(account_id) THEN amount
+            ELSE to_amount
+        END >= sqlc.narg(min_amount)
+    )
+    AND (
+        sqlc.narg(max_amount)::bigint IS NULL
+        OR CASE
+            WHEN from_account_id = sqlc.arg(account_id) THEN amount
+            ELSE to_amount
+        END <= sqlc.narg(max_amount)
+    )
+    AND (
+        sqlc.narg(from_time)::timestamptz IS NULL
+        OR created_at >= sqlc.narg(from_time)
+    )
+    AND (
+        sqlc.narg(to_time)::timestamptz IS NULL
+        OR created_at < sqlc.narg(to_time)
+    )
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) > (
+            sqlc.narg(cursor_created_at),
+            sqlc.narg(cursor_id)::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Statuses of an account. Only active accounts can send or receive money.
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+// accountStatusTransitions lists the statuses each status can move to.
+// Closed is final.
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive, AccountStatusClosed},
+}
+
+// UpdateAccountStatusTxParams contains the input parameters of the update account status transaction
+type UpdateAccountStatusTxParams struct {
+	Status    string `json:"status"`
+	AccountID int64  `json:"account_id"`
+}
+
+// UpdateAccountStatusTx moves an account to another status. Accounts can only be
+// closed once their balance is zero and no funds are held on them.
+func (store *SQLStore) UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		var err error
+
+		account, err = q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(accountStatusTransitions[account.Status], arg.Status) {
+			return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, account.Status, arg.Status)
+		}
+
+		if arg.Status == AccountStatusClosed {
+			account, err = releaseExpiredHolds(ctx, q, account)
+			if err != nil {
+				return err
+			}
+
+			if account.Balance != 0 || account.HeldBalance != 0 {
+				return ErrAccountNotEmpty
+			}
+		}
+
+		account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     account.ID,
+			Status: arg.Status,
+		})
+		return err
+	})
+
+	return account, err
+}
+
+// requireActive checks that money can move in and out of the given accounts
+func requireActive(accounts ...Account) error {
+	for _, account := range accounts {
+		if account.Status != AccountStatusActive {
+			return fmt.Errorf("%w: account [%d] is %s", ErrAccountNotActive, account.ID, account.Status)
+		}
+	}
+	return nil
+}
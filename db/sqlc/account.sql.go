@@ -7,13 +7,15 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_balance, status
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +32,35 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
+	)
+	return i, err
+}
+
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, held_balance, status
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
 	)
 	return i, err
 }
@@ -37,7 +68,7 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (owner, balance, currency)
 VALUES ($1, $2, $3)
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_balance, status
 `
 
 type CreateAccountParams struct {
@@ -55,22 +86,14 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
-`
-
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteAccount, id)
-	return err
-}
-
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at
+SELECT id, owner, balance, currency, created_at, held_balance, status
 FROM accounts
 WHERE id = $1
 LIMIT 1
@@ -85,12 +108,14 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at
+SELECT id, owner, balance, currency, created_at, held_balance, status
 FROM accounts
 WHERE id = $1
 LIMIT 1 FOR NO KEY
@@ -106,24 +131,125 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getSystemAccount = `-- name: GetSystemAccount :one
+SELECT id, owner, balance, currency, created_at, held_balance, status
+FROM accounts
+WHERE owner = '_system'
+    AND currency = $1
+LIMIT 1
+`
+
+// Settlement account of a currency, owned by the system user
+func (q *Queries) GetSystemAccount(ctx context.Context, currency string) (Account, error) {
+	row := q.db.QueryRow(ctx, getSystemAccount, currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at
+SELECT id, owner, balance, currency, created_at, held_balance, status
 FROM accounts
-ORDER BY id
-LIMIT $1 OFFSET $2
+WHERE owner = $1
+    AND (
+        $2::timestamptz IS NULL
+        OR (created_at, id) > (
+            $2,
+            $3::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT $5 OFFSET $4
 `
 
 type ListAccountsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Owner           string             `json:"owner"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	Offset          int32              `json:"offset"`
+	Limit           int32              `json:"limit"`
 }
 
+// Pages are either read with OFFSET or, when the cursor is set,
+// from the (created_at, id) key of the last row of the previous page.
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.Query(ctx, listAccounts, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts,
+		arg.Owner,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllAccounts = `-- name: ListAllAccounts :many
+SELECT id, owner, balance, currency, created_at, held_balance, status
+FROM accounts
+WHERE (
+        $1::timestamptz IS NULL
+        OR (created_at, id) > (
+            $1,
+            $2::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT $4 OFFSET $3
+`
+
+type ListAllAccountsParams struct {
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	Offset          int32              `json:"offset"`
+	Limit           int32              `json:"limit"`
+}
+
+func (q *Queries) ListAllAccounts(ctx context.Context, arg ListAllAccountsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAllAccounts,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +263,66 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAccountsByOwner = `-- name: SearchAccountsByOwner :many
+SELECT id, owner, balance, currency, created_at, held_balance, status
+FROM accounts
+WHERE owner LIKE $1::varchar || '%'
+    AND (
+        $2::timestamptz IS NULL
+        OR (created_at, id) > (
+            $2,
+            $3::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT $5 OFFSET $4
+`
+
+type SearchAccountsByOwnerParams struct {
+	OwnerPrefix     string             `json:"owner_prefix"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	Offset          int32              `json:"offset"`
+	Limit           int32              `json:"limit"`
+}
+
+func (q *Queries) SearchAccountsByOwner(ctx context.Context, arg SearchAccountsByOwnerParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, searchAccountsByOwner,
+		arg.OwnerPrefix,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -152,7 +338,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, held_balance, status
 `
 
 type UpdateAccountParams struct {
@@ -169,6 +355,35 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, held_balance, status
+`
+
+type UpdateAccountStatusParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.ID, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
 	)
 	return i, err
 }
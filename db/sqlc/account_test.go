@@ -2,17 +2,26 @@ package db
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aronreisx/bubblebank/currency"
 	"github.com/aronreisx/bubblebank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
 func createRandomAccount(t *testing.T) Account {
+	return createRandomAccountWithBalance(t, util.RandomMoney())
+}
+
+func createRandomAccountWithBalance(t *testing.T, balance int64) Account {
+	user := createRandomUser(t)
+
 	arg := CreateAccountParams{
-		Owner:    util.RandomOwner(),
-		Balance:  util.RandomMoney(),
+		Owner:    user.Username,
+		Balance:  balance,
 		Currency: util.RandomCurrency(),
 	}
 
@@ -108,36 +117,208 @@ func TestUpdateAccount(t *testing.T) {
 	)
 }
 
-func TestDeleteAccount(t *testing.T) {
-	createdAccount := createRandomAccount(t)
-	err := testQueries.DeleteAccount(context.Background(), createdAccount.ID)
+func TestUpdateAccountStatusTx(t *testing.T) {
+	store := NewStore(testConnPool)
+	account := createRandomAccountWithBalance(t, 10)
+
+	frozen, err := store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+	})
 	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, frozen.Status)
+
+	_, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+	})
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	// Money cannot be closed away
+	_, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	active, err := store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusActive,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, active.Status)
 
-	unavailableAccount, err := testQueries.GetAccount(
-		context.Background(),
-		createdAccount.ID,
-	)
+	_, err = testQueries.UpdateAccount(context.Background(), UpdateAccountParams{ID: account.ID, Balance: 0})
+	require.NoError(t, err)
+
+	closed, err := store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, closed.Status)
+
+	// Closed accounts are kept, and cannot be reopened
+	_, err = store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusActive,
+	})
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	// Their owner can open a new account in the same currency
+	_, err = testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxInactiveAccount(t *testing.T) {
+	store := NewStore(testConnPool)
 
-	require.Error(t, err)
-	require.Empty(t, unavailableAccount)
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccountWithBalance(t, 100)
+
+	_, err := store.UpdateAccountStatusTx(context.Background(), UpdateAccountStatusTxParams{
+		AccountID: account2.ID,
+		Status:    AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	// Frozen accounts can neither send nor receive money
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID: account2.ID,
+		Kind:      EntryKindDeposit,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+}
+
+func TestCreateAccountDuplicateCurrency(t *testing.T) {
+	store := NewStore(testConnPool)
+	account := createRandomAccount(t)
+
+	_, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Balance:  0,
+		Currency: account.Currency,
+	})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+}
+
+func TestCreateAccountUnknownOwner(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	_, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    util.RandomOwner(),
+		Balance:  0,
+		Currency: util.RandomCurrency(),
+	})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
 }
 
 func TestListAccounts(t *testing.T) {
+	user := createRandomUser(t)
+
+	// An owner has at most one open account per currency
+	currencies := currency.Codes()[:3]
+	for _, code := range currencies {
+		_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  util.RandomMoney(),
+			Currency: code,
+		})
+		require.NoError(t, err)
+	}
+	otherAccount := createRandomAccount(t)
+
+	arg := ListAccountsParams{
+		Owner:  user.Username,
+		Limit:  5,
+		Offset: 0,
+	}
+
+	accounts, err := testQueries.ListAccounts(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, accounts, len(currencies))
+
+	for i, account := range accounts {
+		require.Equal(t, user.Username, account.Owner)
+		require.Equal(t, currencies[i], account.Currency)
+		require.NotEqual(t, otherAccount.ID, account.ID)
+	}
+}
+
+func TestListAllAccounts(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		createRandomAccount(t)
 	}
 
-	arg := ListAccountsParams{
+	arg := ListAllAccountsParams{
 		Limit:  5,
 		Offset: 5,
 	}
 
-	accounts, err := testQueries.ListAccounts(context.Background(), arg)
+	accounts, err := testQueries.ListAllAccounts(context.Background(), arg)
 
 	require.NoError(t, err)
-	require.Len(t, accounts, int(arg.Limit))
+	require.Len(t, accounts, 5)
 
-	for _, account := range accounts {
-		require.NotEmpty(t, account)
+	for i := 1; i < len(accounts); i++ {
+		require.Less(t, accounts[i-1].ID, accounts[i].ID)
 	}
 }
+
+func TestSearchAccountsByOwner(t *testing.T) {
+	account := createRandomAccount(t)
+
+	arg := SearchAccountsByOwnerParams{
+		OwnerPrefix: account.Owner[:3],
+		Limit:       10,
+		Offset:      0,
+	}
+
+	accounts, err := testQueries.SearchAccountsByOwner(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, accounts)
+	require.Contains(t, accounts, account)
+
+	for _, found := range accounts {
+		require.True(t, strings.HasPrefix(found.Owner, arg.OwnerPrefix))
+	}
+}
+
+func TestListAllAccountsKeyset(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	arg := ListAllAccountsParams{
+		CursorCreatedAt: pgtype.Timestamptz{Time: account1.CreatedAt, Valid: true},
+		CursorID:        pgtype.Int8{Int64: account1.ID, Valid: true},
+		Limit:           2,
+	}
+
+	accounts, err := testQueries.ListAllAccounts(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, account2.ID, accounts[0].ID)
+	require.Equal(t, account3.ID, accounts[1].ID)
+}
@@ -7,33 +7,58 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createEntry = `-- name: CreateEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES ($1, $2)
-RETURNING id, account_id, amount, created_at
+INSERT INTO entries (
+        account_id,
+        amount,
+        kind,
+        memo,
+        transfer_id,
+        balance_after
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, account_id, amount, created_at, kind, memo, transfer_id, balance_after
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID    int64       `json:"account_id"`
+	Amount       int64       `json:"amount"`
+	Kind         string      `json:"kind"`
+	Memo         string      `json:"memo"`
+	TransferID   pgtype.Int8 `json:"transfer_id"`
+	BalanceAfter int64       `json:"balance_after"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.Kind,
+		arg.Memo,
+		arg.TransferID,
+		arg.BalanceAfter,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Memo,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, kind, memo, transfer_id, balance_after
 FROM entries
 WHERE ID = $1
 LIMIT 1
@@ -47,12 +72,149 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Kind,
+		&i.Memo,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
+const listAccountEntries = `-- name: ListAccountEntries :many
+SELECT id,
+    account_id,
+    amount,
+    created_at,
+    kind,
+    memo,
+    transfer_id,
+    balance_after AS running_balance
+FROM entries
+WHERE account_id = $1
+    AND (
+        $2::timestamptz IS NULL
+        OR created_at >= $2
+    )
+    AND (
+        $3::timestamptz IS NULL
+        OR created_at < $3
+    )
+    AND (
+        $4::varchar IS NULL
+        OR (
+            $4 = 'credit'
+            AND amount > 0
+        )
+        OR (
+            $4 = 'debit'
+            AND amount < 0
+        )
+    )
+    AND (
+        $5::bigint IS NULL
+        OR abs(amount) >= $5
+    )
+    AND (
+        $6::bigint IS NULL
+        OR abs(amount) <= $6
+    )
+    AND (
+        $7::timestamptz IS NULL
+        OR (
+            $8::boolean
+            AND (created_at, id) < (
+                $7,
+                $9::bigint
+            )
+        )
+        OR (
+            NOT $8::boolean
+            AND (created_at, id) > (
+                $7,
+                $9::bigint
+            )
+        )
+    )
+ORDER BY CASE
+        WHEN $8::boolean THEN created_at
+    END DESC,
+    CASE
+        WHEN $8::boolean THEN id
+    END DESC,
+    created_at,
+    id
+LIMIT $11 OFFSET $10
+`
+
+type ListAccountEntriesParams struct {
+	AccountID       int64              `json:"account_id"`
+	FromTime        pgtype.Timestamptz `json:"from_time"`
+	ToTime          pgtype.Timestamptz `json:"to_time"`
+	Direction       pgtype.Text        `json:"direction"`
+	MinAmount       pgtype.Int8        `json:"min_amount"`
+	MaxAmount       pgtype.Int8        `json:"max_amount"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	SortDesc        bool               `json:"sort_desc"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	Offset          int32              `json:"offset"`
+	Limit           int32              `json:"limit"`
+}
+
+type ListAccountEntriesRow struct {
+	ID             int64       `json:"id"`
+	AccountID      int64       `json:"account_id"`
+	Amount         int64       `json:"amount"`
+	CreatedAt      time.Time   `json:"created_at"`
+	Kind           string      `json:"kind"`
+	Memo           string      `json:"memo"`
+	TransferID     pgtype.Int8 `json:"transfer_id"`
+	RunningBalance int64       `json:"running_balance"`
+}
+
+// Entries of an account with the balance right after each entry
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listAccountEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Direction,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CursorCreatedAt,
+		arg.SortDesc,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEntriesRow{}
+	for rows.Next() {
+		var i ListAccountEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.Memo,
+			&i.TransferID,
+			&i.RunningBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at
+SELECT id, account_id, amount, created_at, kind, memo, transfer_id, balance_after
 FROM entries
 WHERE account_id = $1
 ORDER BY id
@@ -79,6 +241,10 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Kind,
+			&i.Memo,
+			&i.TransferID,
+			&i.BalanceAfter,
 		); err != nil {
 			return nil, err
 		}
@@ -89,3 +255,25 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const sumAccountEntriesSince = `-- name: SumAccountEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1
+    AND kind = $2
+    AND created_at >= $3
+`
+
+type SumAccountEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	Kind      string    `json:"kind"`
+	Since     time.Time `json:"since"`
+}
+
+// Total amount of the entries of a kind posted on an account since a time
+func (q *Queries) SumAccountEntriesSince(ctx context.Context, arg SumAccountEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumAccountEntriesSince, arg.AccountID, arg.Kind, arg.Since)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// createStatement moves amounts in and out of a new account opened with openingBalance
+func createStatement(t *testing.T, openingBalance int64, amounts []int64) Account {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccountWithBalance(t, openingBalance)
+	other := createRandomAccountWithBalance(t, 1000)
+
+	for _, amount := range amounts {
+		arg := TransferTxParams{FromAccountID: other.ID, ToAccountID: account.ID, Amount: amount}
+		if amount < 0 {
+			arg = TransferTxParams{FromAccountID: account.ID, ToAccountID: other.ID, Amount: -amount}
+		}
+
+		_, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	return account
+}
+
+func TestListAccountEntries(t *testing.T) {
+	amounts := []int64{50, -20, 30, -10, 5}
+	account := createStatement(t, 100, amounts)
+
+	entries, err := testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		Limit:     10,
+		Offset:    0,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, len(amounts))
+
+	balance := account.Balance
+	for i, entry := range entries {
+		balance += amounts[i]
+		require.Equal(t, account.ID, entry.AccountID)
+		require.Equal(t, amounts[i], entry.Amount)
+		require.Equal(t, balance, entry.RunningBalance)
+	}
+}
+
+func TestListAccountEntriesFilters(t *testing.T) {
+	amounts := []int64{50, -20, 30, -10, 5}
+	account := createStatement(t, 100, amounts)
+
+	// Debits only, newest first
+	entries, err := testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		Direction: pgtype.Text{String: "debit", Valid: true},
+		SortDesc:  true,
+		Limit:     10,
+		Offset:    0,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, int64(-10), entries[0].Amount)
+	require.Equal(t, int64(150), entries[0].RunningBalance)
+	require.Equal(t, int64(-20), entries[1].Amount)
+	require.Equal(t, int64(130), entries[1].RunningBalance)
+
+	// Credits between 10 and 40
+	entries, err = testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		Direction: pgtype.Text{String: "credit", Valid: true},
+		MinAmount: pgtype.Int8{Int64: 10, Valid: true},
+		MaxAmount: pgtype.Int8{Int64: 40, Valid: true},
+		Limit:     10,
+		Offset:    0,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, int64(30), entries[0].Amount)
+	require.Equal(t, int64(160), entries[0].RunningBalance)
+
+	// Time range excluding every entry
+	last := entries[0]
+	entries, err = testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		ToTime:    pgtype.Timestamptz{Time: last.CreatedAt.AddDate(0, 0, -1), Valid: true},
+		Limit:     10,
+		Offset:    0,
+	})
+
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes the store translates into sentinel errors
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+var (
+	// ErrRecordNotFound is returned when a query expected a row but got none
+	ErrRecordNotFound = pgx.ErrNoRows
+	// ErrUniqueViolation is returned when a write conflicts with a unique constraint
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	// ErrForeignKeyViolation is returned when a write references a missing row
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	// ErrSerializationFailure is returned when a transaction could not be serialized
+	ErrSerializationFailure = errors.New("could not serialize transaction")
+	// ErrDeadlockDetected is returned when a transaction was aborted to break a deadlock
+	ErrDeadlockDetected = errors.New("deadlock detected")
+)
+
+// Error is a database error translated into one of the store's sentinel errors.
+// Both the sentinel and the original driver error can be matched with errors.Is and errors.As.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// ErrorCode returns the PostgreSQL error code of err, or an empty string if err is not a PostgreSQL error
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// translateError wraps PostgreSQL errors with the matching sentinel error
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var kind error
+	switch ErrorCode(err) {
+	case uniqueViolation:
+		kind = ErrUniqueViolation
+	case foreignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case serializationFailure:
+		kind = ErrSerializationFailure
+	case deadlockDetected:
+		kind = ErrDeadlockDetected
+	default:
+		return err
+	}
+
+	return &Error{Kind: kind, Err: err}
+}
+
+// translatingDBTX wraps a DBTX so that every query error goes through translateError
+type translatingDBTX struct {
+	DBTX
+}
+
+func (db translatingDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := db.DBTX.Exec(ctx, sql, args...)
+	return tag, translateError(err)
+}
+
+func (db translatingDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	rows, err := db.DBTX.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return translatingRows{rows}, nil
+}
+
+func (db translatingDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return translatingRow{db.DBTX.QueryRow(ctx, sql, args...)}
+}
+
+type translatingRow struct {
+	pgx.Row
+}
+
+func (r translatingRow) Scan(dest ...any) error {
+	return translateError(r.Row.Scan(dest...))
+}
+
+type translatingRows struct {
+	pgx.Rows
+}
+
+func (r translatingRows) Scan(dest ...any) error {
+	return translateError(r.Rows.Scan(dest...))
+}
+
+func (r translatingRows) Err() error {
+	return translateError(r.Rows.Err())
+}
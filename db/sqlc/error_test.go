@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	require.NoError(t, translateError(nil))
+
+	plainErr := errors.New("plain error")
+	require.Equal(t, plainErr, translateError(plainErr))
+
+	testCases := map[string]error{
+		uniqueViolation:      ErrUniqueViolation,
+		foreignKeyViolation:  ErrForeignKeyViolation,
+		serializationFailure: ErrSerializationFailure,
+		deadlockDetected:     ErrDeadlockDetected,
+	}
+
+	for code, kind := range testCases {
+		pgErr := &pgconn.PgError{Code: code}
+		err := translateError(pgErr)
+
+		require.ErrorIs(t, err, kind)
+		require.ErrorIs(t, err, pgErr)
+		require.Equal(t, code, ErrorCode(err))
+	}
+}
+
+func TestForeignKeyViolation(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	_, err := store.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: -1,
+		Amount:    10,
+		Kind:      EntryKindTransfer,
+	})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
+}
+
+func TestRecordNotFound(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	_, err := store.GetAccount(context.Background(), -1)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,80 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: fx_rate.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFXRate = `-- name: CreateFXRate :one
+INSERT INTO fx_rates (
+        base_currency,
+        quote_currency,
+        rate,
+        effective_at
+    )
+VALUES ($1, $2, $3, $4)
+RETURNING id, base_currency, quote_currency, rate, effective_at, created_at
+`
+
+type CreateFXRateParams struct {
+	BaseCurrency  string         `json:"base_currency"`
+	QuoteCurrency string         `json:"quote_currency"`
+	Rate          pgtype.Numeric `json:"rate"`
+	EffectiveAt   time.Time      `json:"effective_at"`
+}
+
+func (q *Queries) CreateFXRate(ctx context.Context, arg CreateFXRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, createFXRate,
+		arg.BaseCurrency,
+		arg.QuoteCurrency,
+		arg.Rate,
+		arg.EffectiveAt,
+	)
+	var i FxRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.EffectiveAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFXRate = `-- name: GetFXRate :one
+SELECT id, base_currency, quote_currency, rate, effective_at, created_at
+FROM fx_rates
+WHERE base_currency = $1
+    AND quote_currency = $2
+    AND effective_at <= $3
+ORDER BY effective_at DESC
+LIMIT 1
+`
+
+type GetFXRateParams struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	At            time.Time `json:"at"`
+}
+
+func (q *Queries) GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error) {
+	row := q.db.QueryRow(ctx, getFXRate, arg.BaseCurrency, arg.QuoteCurrency, arg.At)
+	var i FxRate
+	err := row.Scan(
+		&i.ID,
+		&i.BaseCurrency,
+		&i.QuoteCurrency,
+		&i.Rate,
+		&i.EffectiveAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
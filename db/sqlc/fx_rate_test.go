@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aronreisx/bubblebank/util"
+)
+
+// randomEffectiveAt returns a random time, so that tests sharing a currency pair
+// don't pick each other's rates
+func randomEffectiveAt() time.Time {
+	return time.Unix(util.RandomInt(0, 1<<40), 0).UTC()
+}
+
+func createTestFXRate(t *testing.T, base, quote, rate string, effectiveAt time.Time) FxRate {
+	var value pgtype.Numeric
+	require.NoError(t, value.Scan(rate))
+
+	arg := CreateFXRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          value,
+		EffectiveAt:   effectiveAt,
+	}
+
+	fxRate, err := testQueries.CreateFXRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, fxRate.ID)
+	require.Equal(t, arg.BaseCurrency, fxRate.BaseCurrency)
+	require.Equal(t, arg.QuoteCurrency, fxRate.QuoteCurrency)
+	require.True(t, fxRate.EffectiveAt.Equal(arg.EffectiveAt))
+	require.NotZero(t, fxRate.CreatedAt)
+
+	return fxRate
+}
+
+func TestCreateFXRate(t *testing.T) {
+	createTestFXRate(t, "USD", "EUR", "0.92", randomEffectiveAt())
+}
+
+func TestCreateFXRateInvalid(t *testing.T) {
+	var rate pgtype.Numeric
+	require.NoError(t, rate.Scan("-1"))
+
+	_, err := testQueries.CreateFXRate(context.Background(), CreateFXRateParams{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          rate,
+		EffectiveAt:   randomEffectiveAt(),
+	})
+	require.Equal(t, "23514", ErrorCode(err))
+
+	require.NoError(t, rate.Scan("1"))
+	_, err = testQueries.CreateFXRate(context.Background(), CreateFXRateParams{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "XXX",
+		Rate:          rate,
+		EffectiveAt:   randomEffectiveAt(),
+	})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
+}
+
+func TestGetFXRate(t *testing.T) {
+	effectiveAt := randomEffectiveAt()
+
+	older := createTestFXRate(t, "EUR", "GBP", "0.85", effectiveAt)
+	newer := createTestFXRate(t, "EUR", "GBP", "0.86", effectiveAt.Add(time.Hour))
+
+	// The latest rate in effect at the given time
+	fxRate, err := testQueries.GetFXRate(context.Background(), GetFXRateParams{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "GBP",
+		At:            effectiveAt.Add(30 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, older.ID, fxRate.ID)
+
+	fxRate, err = testQueries.GetFXRate(context.Background(), GetFXRateParams{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "GBP",
+		At:            effectiveAt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, newer.ID, fxRate.ID)
+}
@@ -0,0 +1,281 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultHoldDuration is how long a hold placed without an expiry lasts
+const DefaultHoldDuration = 7 * 24 * time.Hour
+
+// Statuses of a hold. Only active holds reserve funds.
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+// AvailableBalance is the part of the balance that is not reserved by active holds
+func (account Account) AvailableBalance() int64 {
+	return account.Balance - account.HeldBalance
+}
+
+// PlaceHoldTxParams contains the input parameters of the place hold transaction
+type PlaceHoldTxParams struct {
+	// ExpiresAt defaults to DefaultHoldDuration from now
+	ExpiresAt   time.Time `json:"expires_at"`
+	Memo        string    `json:"memo"`
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+}
+
+// HoldTxResult is the result of placing or releasing a hold
+type HoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// PlaceHoldTx reserves funds of an account for a later transfer to another account.
+// The funds stay on the account but no longer count towards its available balance.
+func (store *SQLStore) PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	if arg.Amount <= 0 {
+		return result, ErrInvalidAmount
+	}
+
+	if arg.AccountID == arg.ToAccountID {
+		return result, ErrSameAccount
+	}
+
+	expiresAt := arg.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(DefaultHoldDuration)
+	}
+	if !expiresAt.After(time.Now()) {
+		return result, ErrInvalidHoldExpiry
+	}
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := requireActive(account, toAccount); err != nil {
+			return err
+		}
+
+		// Captures are plain transfers, they cannot convert currencies
+		if account.Currency != toAccount.Currency {
+			return ErrCurrencyMismatch
+		}
+
+		account, err = releaseExpiredHolds(ctx, q, account)
+		if err != nil {
+			return err
+		}
+
+		if account.AvailableBalance() < arg.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount,
+			Memo:        arg.Memo,
+			ExpiresAt:   expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// CaptureHoldTxParams contains the input parameters of the capture hold transaction
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount defaults to the whole hold, the part of the hold that is not captured is released
+	Amount int64 `json:"amount"`
+}
+
+// CaptureHoldTxResult is the result of the capture hold transaction
+type CaptureHoldTxResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHoldTx turns an active hold into a transfer to the account it was placed for.
+// A hold is captured once, in full or in part.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	if arg.Amount < 0 {
+		return result, ErrInvalidAmount
+	}
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		hold, err := lockHold(ctx, q, arg.HoldID, true)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		// Free the whole hold first, so that the transfer can spend it
+		_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = postTransfer(ctx, q, CreateTransferParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+			ToAmount:      amount,
+		}, hold.Memo)
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CloseHold(ctx, CloseHoldParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+			TransferID:     pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ReleaseHoldTx cancels an active hold, making its funds available again
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error) {
+	var result HoldTxResult
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		hold, err := lockHold(ctx, q, holdID, false)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CloseHold(ctx, CloseHoldParams{
+			ID:     hold.ID,
+			Status: HoldStatusReleased,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireHoldsTx releases the funds of the active holds past their expiry and returns
+// the number of accounts they were placed on. Each account is handled in a transaction
+// of its own that locks the account before its holds, as transfers do, so that the
+// sweep does not deadlock with them and does not keep many accounts locked at once.
+func (store *SQLStore) ExpireHoldsTx(ctx context.Context) (int64, error) {
+	accountIDs, err := store.ListAccountsWithExpiredHolds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired int64
+	for _, accountID := range accountIDs {
+		err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+			account, err := q.GetAccountForUpdate(ctx, accountID)
+			if err != nil {
+				return err
+			}
+
+			_, err = releaseExpiredHolds(ctx, q, account)
+			return err
+		})
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// lockHold locks an active hold along with the accounts a capture or a release updates.
+// Accounts are locked before the hold, as transfers and the expiry sweep do.
+// Holds past their expiry can still be released but no longer captured.
+func lockHold(ctx context.Context, q *Queries, holdID int64, capture bool) (Hold, error) {
+	hold, err := q.GetHold(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if capture {
+		_, _, err = lockAccounts(ctx, q, hold.AccountID, hold.ToAccountID)
+	} else {
+		_, err = q.GetAccountForUpdate(ctx, hold.AccountID)
+	}
+	if err != nil {
+		return hold, err
+	}
+
+	// Read the hold again now that nobody else can close it
+	hold, err = q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if hold.Status != HoldStatusActive {
+		return hold, ErrHoldNotActive
+	}
+	if capture && !hold.ExpiresAt.After(time.Now()) {
+		return hold, ErrHoldNotActive
+	}
+
+	return hold, nil
+}
+
+// releaseExpiredHolds expires the holds of a locked account that are past their expiry
+// and returns the account with their funds available again
+func releaseExpiredHolds(ctx context.Context, q *Queries, account Account) (Account, error) {
+	if account.HeldBalance == 0 {
+		return account, nil
+	}
+
+	return q.ExpireAccountHolds(ctx, account.ID)
+}
@@ -0,0 +1,216 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: hold.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const closeHold = `-- name: CloseHold :one
+UPDATE holds
+SET status = $2,
+    captured_amount = $3,
+    transfer_id = $4,
+    closed_at = now()
+WHERE id = $1
+RETURNING id, account_id, to_account_id, amount, captured_amount, status, transfer_id, memo, expires_at, closed_at, created_at
+`
+
+type CloseHoldParams struct {
+	ID             int64       `json:"id"`
+	Status         string      `json:"status"`
+	CapturedAmount int64       `json:"captured_amount"`
+	TransferID     pgtype.Int8 `json:"transfer_id"`
+}
+
+func (q *Queries) CloseHold(ctx context.Context, arg CloseHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, closeHold,
+		arg.ID,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.TransferID,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.Memo,
+		&i.ExpiresAt,
+		&i.ClosedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (
+        account_id,
+        to_account_id,
+        amount,
+        memo,
+        expires_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, account_id, to_account_id, amount, captured_amount, status, transfer_id, memo, expires_at, closed_at, created_at
+`
+
+type CreateHoldParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	Memo        string    `json:"memo"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, createHold,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.Memo,
+		&i.ExpiresAt,
+		&i.ClosedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const expireAccountHolds = `-- name: ExpireAccountHolds :one
+WITH expired AS (
+    UPDATE holds
+    SET status = 'expired',
+        closed_at = now()
+    WHERE holds.account_id = $1
+        AND status = 'active'
+        AND expires_at <= now()
+    RETURNING amount
+)
+UPDATE accounts
+SET held_balance = held_balance - (
+        SELECT COALESCE(SUM(amount), 0)
+        FROM expired
+    )
+WHERE accounts.id = $1
+RETURNING id, owner, balance, currency, created_at, held_balance, status
+`
+
+// Expires the active holds of an account past their expiry and frees their funds
+func (q *Queries) ExpireAccountHolds(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRow(ctx, expireAccountHolds, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, memo, expires_at, closed_at, created_at
+FROM holds
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.Memo,
+		&i.ExpiresAt,
+		&i.ClosedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, memo, expires_at, closed_at, created_at
+FROM holds
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHoldForUpdate, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.Memo,
+		&i.ExpiresAt,
+		&i.ClosedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAccountsWithExpiredHolds = `-- name: ListAccountsWithExpiredHolds :many
+SELECT DISTINCT account_id
+FROM holds
+WHERE status = 'active'
+    AND expires_at <= now()
+ORDER BY account_id
+`
+
+// Accounts with active holds past their expiry, in the order accounts are locked in
+func (q *Queries) ListAccountsWithExpiredHolds(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listAccountsWithExpiredHolds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var account_id int64
+		if err := rows.Scan(&account_id); err != nil {
+			return nil, err
+		}
+		items = append(items, account_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createRandomAccountPair creates two accounts of the same currency
+func createRandomAccountPair(t *testing.T, balance int64) (Account, Account) {
+	account1 := createRandomAccountWithBalance(t, balance)
+
+	account2, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  0,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+
+	return account1, account2
+}
+
+func TestPlaceHoldTx(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	result, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      60,
+		Memo:        "card authorization",
+	})
+	require.NoError(t, err)
+
+	hold := result.Hold
+	require.NotZero(t, hold.ID)
+	require.Equal(t, account1.ID, hold.AccountID)
+	require.Equal(t, account2.ID, hold.ToAccountID)
+	require.Equal(t, int64(60), hold.Amount)
+	require.Equal(t, HoldStatusActive, hold.Status)
+	require.Equal(t, "card authorization", hold.Memo)
+	require.WithinDuration(t, time.Now().Add(DefaultHoldDuration), hold.ExpiresAt, time.Minute)
+
+	// The ledger balance is unchanged, only the available balance goes down
+	require.Equal(t, int64(100), result.Account.Balance)
+	require.Equal(t, int64(60), result.Account.HeldBalance)
+	require.Equal(t, int64(40), result.Account.AvailableBalance())
+
+	// Held funds cannot be spent twice
+	_, err = store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(60), transfer.FromAccount.Balance)
+	require.Zero(t, transfer.FromAccount.AvailableBalance())
+}
+
+func TestPlaceHoldTxInvalidParams(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	_, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      0,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account1.ID,
+		Amount:      10,
+	})
+	require.ErrorIs(t, err, ErrSameAccount)
+
+	_, err = store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      10,
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	})
+	require.ErrorIs(t, err, ErrInvalidHoldExpiry)
+
+	otherCurrency := "USD"
+	if account1.Currency == otherCurrency {
+		otherCurrency = "EUR"
+	}
+	account3, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Currency: otherCurrency,
+	})
+	require.NoError(t, err)
+
+	_, err = store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account3.ID,
+		Amount:      10,
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	placed, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      60,
+		Memo:        "card authorization",
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: placed.Hold.ID,
+		Amount: 61,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	// A partial capture moves the captured amount and releases the rest
+	result, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: placed.Hold.ID,
+		Amount: 45,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, int64(45), result.Hold.CapturedAmount)
+	require.True(t, result.Hold.ClosedAt.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Hold.TransferID.Int64)
+
+	require.Equal(t, int64(45), result.Transfer.Transfer.Amount)
+	require.Equal(t, "card authorization", result.Transfer.FromEntry.Memo)
+	require.Equal(t, int64(55), result.Transfer.FromAccount.Balance)
+	require.Zero(t, result.Transfer.FromAccount.HeldBalance)
+	require.Equal(t, int64(45), result.Transfer.ToAccount.Balance)
+
+	// A hold is captured once
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: placed.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	_, err = store.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	placed, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      60,
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusReleased, result.Hold.Status)
+	require.Zero(t, result.Hold.CapturedAmount)
+	require.False(t, result.Hold.TransferID.Valid)
+	require.Equal(t, int64(100), result.Account.Balance)
+	require.Equal(t, int64(100), result.Account.AvailableBalance())
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: placed.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestHoldExpiry(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	placed, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      100,
+		ExpiresAt:   time.Now().Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+
+	// Expired holds cannot be captured and no longer reserve funds
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: placed.Hold.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+	require.Zero(t, transfer.FromAccount.Balance)
+	require.Zero(t, transfer.FromAccount.HeldBalance)
+
+	hold, err := testQueries.GetHold(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusExpired, hold.Status)
+}
+
+func TestExpireHolds(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	placed, err := store.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      30,
+		ExpiresAt:   time.Now().Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+
+	expired, err := store.ExpireHoldsTx(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, expired, int64(1))
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.HeldBalance)
+
+	hold, err := testQueries.GetHold(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusExpired, hold.Status)
+	require.True(t, hold.ClosedAt.Valid)
+}
@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :one
+UPDATE idempotency_keys
+SET status = 'completed',
+    response_code = $3,
+    response_body = $4
+WHERE username = $1
+    AND key = $2
+    AND status = 'in_progress'
+    AND locked_at = $5
+RETURNING username, key, request_hash, status, response_code, response_body, locked_at, created_at
+`
+
+type CompleteIdempotencyKeyParams struct {
+	Username     string    `json:"username"`
+	Key          string    `json:"key"`
+	ResponseCode int32     `json:"response_code"`
+	ResponseBody []byte    `json:"response_body"`
+	LockedAt     time.Time `json:"locked_at"`
+}
+
+// Stores the response of the request holding the key since locked_at.
+// No row is returned when a retry took the key over in the meantime.
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, completeIdempotencyKey,
+		arg.Username,
+		arg.Key,
+		arg.ResponseCode,
+		arg.ResponseBody,
+		arg.LockedAt,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.Status,
+		&i.ResponseCode,
+		&i.ResponseBody,
+		&i.LockedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (username, key, request_hash)
+VALUES ($1, $2, $3) ON CONFLICT (username, key) DO
+UPDATE
+SET locked_at = now()
+WHERE idempotency_keys.status = 'in_progress'
+    AND idempotency_keys.request_hash = EXCLUDED.request_hash
+    AND idempotency_keys.locked_at < $4
+RETURNING username, key, request_hash, status, response_code, response_body, locked_at, created_at
+`
+
+type CreateIdempotencyKeyParams struct {
+	Username    string    `json:"username"`
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"`
+	StaleBefore time.Time `json:"stale_before"`
+}
+
+// Claims a key for a new request. A key left in progress since before
+// stale_before by a request that never finished is taken over, as long as
+// the request is the same. No row is returned when the key is already taken.
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, createIdempotencyKey,
+		arg.Username,
+		arg.Key,
+		arg.RequestHash,
+		arg.StaleBefore,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.Status,
+		&i.ResponseCode,
+		&i.ResponseBody,
+		&i.LockedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE username = $1
+    AND key = $2
+    AND status = 'in_progress'
+    AND locked_at = $3
+`
+
+type DeleteIdempotencyKeyParams struct {
+	Username string    `json:"username"`
+	Key      string    `json:"key"`
+	LockedAt time.Time `json:"locked_at"`
+}
+
+// Frees the key held since locked_at, leaving a key taken over by a retry alone
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, deleteIdempotencyKey, arg.Username, arg.Key, arg.LockedAt)
+	return err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT username, key, request_hash, status, response_code, response_body, locked_at, created_at
+FROM idempotency_keys
+WHERE username = $1
+    AND key = $2
+LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.Username, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.Status,
+		&i.ResponseCode,
+		&i.ResponseBody,
+		&i.LockedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
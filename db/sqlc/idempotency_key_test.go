@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomIdempotencyKey(t *testing.T) IdempotencyKey {
+	user := createRandomUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+		StaleBefore: time.Now().Add(-time.Minute),
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, key.Username)
+	require.Equal(t, arg.Key, key.Key)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.Equal(t, "in_progress", key.Status)
+	require.Nil(t, key.ResponseBody)
+	require.NotZero(t, key.LockedAt)
+
+	return key
+}
+
+func TestCreateIdempotencyKeyTaken(t *testing.T) {
+	key := createRandomIdempotencyKey(t)
+
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    key.Username,
+		Key:         key.Key,
+		RequestHash: key.RequestHash,
+		StaleBefore: time.Now().Add(-time.Minute),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCreateIdempotencyKeyTakeOverStale(t *testing.T) {
+	key := createRandomIdempotencyKey(t)
+
+	// A different request never takes the key over
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    key.Username,
+		Key:         key.Key,
+		RequestHash: util.RandomString(64),
+		StaleBefore: time.Now().Add(time.Minute),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// The same request takes over a key held since before StaleBefore
+	retaken, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    key.Username,
+		Key:         key.Key,
+		RequestHash: key.RequestHash,
+		StaleBefore: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.True(t, retaken.LockedAt.After(key.LockedAt))
+}
+
+func TestCompleteIdempotencyKey(t *testing.T) {
+	key := createRandomIdempotencyKey(t)
+
+	arg := CompleteIdempotencyKeyParams{
+		Username:     key.Username,
+		Key:          key.Key,
+		ResponseCode: 200,
+		ResponseBody: []byte(`{"id":1}`),
+		LockedAt:     key.LockedAt,
+	}
+
+	completed, err := testQueries.CompleteIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, "completed", completed.Status)
+	require.Equal(t, arg.ResponseCode, completed.ResponseCode)
+	require.Equal(t, arg.ResponseBody, completed.ResponseBody)
+
+	// A completed key is neither completed again nor released
+	_, err = testQueries.CompleteIdempotencyKey(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testQueries.DeleteIdempotencyKey(context.Background(), DeleteIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+		LockedAt: key.LockedAt,
+	})
+	require.NoError(t, err)
+
+	stored, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "completed", stored.Status)
+}
+
+func TestCompleteIdempotencyKeyTakenOver(t *testing.T) {
+	key := createRandomIdempotencyKey(t)
+
+	retaken, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    key.Username,
+		Key:         key.Key,
+		RequestHash: key.RequestHash,
+		StaleBefore: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	// The request that lost the key can neither complete nor release it
+	_, err = testQueries.CompleteIdempotencyKey(context.Background(), CompleteIdempotencyKeyParams{
+		Username:     key.Username,
+		Key:          key.Key,
+		ResponseCode: 200,
+		ResponseBody: []byte(`{"id":1}`),
+		LockedAt:     key.LockedAt,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = testQueries.DeleteIdempotencyKey(context.Background(), DeleteIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+		LockedAt: key.LockedAt,
+	})
+	require.NoError(t, err)
+
+	stored, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "in_progress", stored.Status)
+	require.True(t, stored.LockedAt.Equal(retaken.LockedAt))
+}
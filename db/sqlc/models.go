@@ -6,6 +6,9 @@ package db
 
 import (
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Account struct {
@@ -14,21 +17,96 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// Sum of the active holds on the account
+	HeldBalance int64  `json:"held_balance"`
+	Status      string `json:"status"`
+}
+
+type Currency struct {
+	Code     string `json:"code"`
+	Exponent int16  `json:"exponent"`
 }
 
 type Entry struct {
 	ID        int64 `json:"id"`
 	AccountID int64 `json:"account_id"`
 	// Can be negative or positive
-	Amount    int64     `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	Amount     int64       `json:"amount"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Kind       string      `json:"kind"`
+	Memo       string      `json:"memo"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	// Balance of the account right after the entry was posted
+	BalanceAfter int64 `json:"balance_after"`
+}
+
+type FxRate struct {
+	ID            int64          `json:"id"`
+	BaseCurrency  string         `json:"base_currency"`
+	QuoteCurrency string         `json:"quote_currency"`
+	Rate          pgtype.Numeric `json:"rate"`
+	EffectiveAt   time.Time      `json:"effective_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type Hold struct {
+	ID             int64              `json:"id"`
+	AccountID      int64              `json:"account_id"`
+	ToAccountID    int64              `json:"to_account_id"`
+	Amount         int64              `json:"amount"`
+	CapturedAmount int64              `json:"captured_amount"`
+	Status         string             `json:"status"`
+	TransferID     pgtype.Int8        `json:"transfer_id"`
+	Memo           string             `json:"memo"`
+	ExpiresAt      time.Time          `json:"expires_at"`
+	ClosedAt       pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username     string    `json:"username"`
+	Key          string    `json:"key"`
+	RequestHash  string    `json:"request_hash"`
+	Status       string    `json:"status"`
+	ResponseCode int32     `json:"response_code"`
+	ResponseBody []byte    `json:"response_body"`
+	LockedAt     time.Time `json:"locked_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	IsBlocked    bool      `json:"is_blocked"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
-	// Must be positive
+	// Must be positive, in the currency of the source account
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// Must be positive, in the currency of the destination account
+	ToAmount int64 `json:"to_amount"`
+	// Exchange rate applied, null when both accounts share a currency
+	Rate   pgtype.Numeric     `json:"rate"`
+	RateAt pgtype.Timestamptz `json:"rate_at"`
+	// Transfer this one partially or fully reverses
+	ReversalOf pgtype.Int8 `json:"reversal_of"`
+}
+
+type User struct {
+	Username          string    `json:"username"`
+	HashedPassword    string    `json:"hashed_password"`
+	FullName          string    `json:"full_name"`
+	Email             string    `json:"email"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
 }
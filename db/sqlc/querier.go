@@ -6,22 +6,74 @@ package db
 
 import (
 	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	CloseHold(ctx context.Context, arg CloseHoldParams) (Hold, error)
+	// Stores the response of the request holding the key since locked_at.
+	// No row is returned when a retry took the key over in the meantime.
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (IdempotencyKey, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFXRate(ctx context.Context, arg CreateFXRateParams) (FxRate, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	// Claims a key for a new request. A key left in progress since before
+	// stale_before by a request that never finished is taken over, as long as
+	// the request is the same. No row is returned when the key is already taken.
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// Frees the key held since locked_at, leaving a key taken over by a retry alone
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	// Expires the active holds of an account past their expiry and frees their funds
+	ExpireAccountHolds(ctx context.Context, id int64) (Account, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// Settlement account of a currency, owned by the system user
+	GetSystemAccount(ctx context.Context, currency string) (Account, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetUser(ctx context.Context, username string) (User, error)
+	// Balance of a batch of accounts next to the sum of their entries
+	ListAccountBalances(ctx context.Context, arg ListAccountBalancesParams) ([]ListAccountBalancesRow, error)
+	// Entries of an account with the balance right after each entry
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error)
+	// Pages are either read with OFFSET or, when the cursor is set,
+	// from the (created_at, id) key of the last row of the previous page.
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Accounts with active holds past their expiry, in the order accounts are locked in
+	ListAccountsWithExpiredHolds(ctx context.Context) ([]int64, error)
+	ListAllAccounts(ctx context.Context, arg ListAllAccountsParams) ([]Account, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// Transfer entries that are not linked to any transfer
+	ListOrphanedEntries(ctx context.Context, arg ListOrphanedEntriesParams) ([]Entry, error)
+	// Entries of a batch of transfers, counting those matching each leg
+	ListTransferEntryCounts(ctx context.Context, arg ListTransferEntryCountsParams) ([]ListTransferEntryCountsRow, error)
+	// Amount filters compare the amount in the currency of the listed account,
+	// to_amount when it received the transfer
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	SearchAccountsByOwner(ctx context.Context, arg SearchAccountsByOwnerParams) ([]Account, error)
+	SumAccountEntries(ctx context.Context, accountID int64) (int64, error)
+	// Total amount of the entries of a kind posted on an account since a time
+	SumAccountEntriesSince(ctx context.Context, arg SumAccountEntriesSinceParams) (int64, error)
+	// Amounts already moved back by the reversals of a transfer
+	SumTransferReversals(ctx context.Context, reversalOf pgtype.Int8) (SumTransferReversalsRow, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
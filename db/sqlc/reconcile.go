@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultReconcileBatchSize is the number of rows read per query when ReconcileParams.BatchSize is not set
+const DefaultReconcileBatchSize = 1000
+
+// ReconcileParams contains the input parameters of the reconciliation
+type ReconcileParams struct {
+	// BatchSize is the number of rows read per query
+	BatchSize int32
+	// Repair sets the balance of drifting accounts to the sum of their entries
+	Repair bool
+}
+
+// BalanceDrift is an account whose balance differs from the sum of its entries
+type BalanceDrift struct {
+	Owner          string `json:"owner"`
+	Currency       string `json:"currency"`
+	AccountID      int64  `json:"account_id"`
+	Balance        int64  `json:"balance"`
+	EntriesBalance int64  `json:"entries_balance"`
+	// Drift is the balance minus the sum of the entries
+	Drift    int64 `json:"drift"`
+	Repaired bool  `json:"repaired"`
+}
+
+// TransferMismatch is a transfer without exactly one debit and one credit entry matching it
+type TransferMismatch struct {
+	TransferID  int64 `json:"transfer_id"`
+	EntryCount  int64 `json:"entry_count"`
+	DebitCount  int64 `json:"debit_count"`
+	CreditCount int64 `json:"credit_count"`
+}
+
+// ReconcileReport is the outcome of a reconciliation run
+type ReconcileReport struct {
+	StartedAt          time.Time          `json:"started_at"`
+	FinishedAt         time.Time          `json:"finished_at"`
+	BalanceDrifts      []BalanceDrift     `json:"balance_drifts"`
+	OrphanedEntries    []Entry            `json:"orphaned_entries"`
+	TransferMismatches []TransferMismatch `json:"transfer_mismatches"`
+	AccountsScanned    int64              `json:"accounts_scanned"`
+	TransfersScanned   int64              `json:"transfers_scanned"`
+}
+
+// Consistent reports whether the run found no issue left unrepaired
+func (report ReconcileReport) Consistent() bool {
+	for _, drift := range report.BalanceDrifts {
+		if !drift.Repaired {
+			return false
+		}
+	}
+	return len(report.OrphanedEntries) == 0 && len(report.TransferMismatches) == 0
+}
+
+// Reconcile checks that the balance of every account equals the sum of its entries,
+// that every transfer entry belongs to a transfer and that every transfer has exactly
+// one matching debit and credit entry. Rows are read in batches of arg.BatchSize.
+// With arg.Repair set, drifting balances are corrected in one transaction per batch.
+func (store *SQLStore) Reconcile(ctx context.Context, arg ReconcileParams) (ReconcileReport, error) {
+	report := ReconcileReport{
+		StartedAt:          time.Now(),
+		BalanceDrifts:      []BalanceDrift{},
+		OrphanedEntries:    []Entry{},
+		TransferMismatches: []TransferMismatch{},
+	}
+
+	batchSize := arg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultReconcileBatchSize
+	}
+
+	if err := store.reconcileBalances(ctx, &report, batchSize, arg.Repair); err != nil {
+		return report, err
+	}
+
+	if err := store.reconcileTransfers(ctx, &report, batchSize); err != nil {
+		return report, err
+	}
+
+	if err := store.findOrphanedEntries(ctx, &report, batchSize); err != nil {
+		return report, err
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (store *SQLStore) reconcileBalances(ctx context.Context, report *ReconcileReport, batchSize int32, repair bool) error {
+	var afterID int64
+	for {
+		accounts, err := store.ListAccountBalances(ctx, ListAccountBalancesParams{
+			AfterID: afterID,
+			Limit:   batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		var drifts []BalanceDrift
+		for _, account := range accounts {
+			if account.Balance != account.EntriesBalance {
+				drifts = append(drifts, BalanceDrift{
+					Owner:          account.Owner,
+					Currency:       account.Currency,
+					AccountID:      account.ID,
+					Balance:        account.Balance,
+					EntriesBalance: account.EntriesBalance,
+					Drift:          account.Balance - account.EntriesBalance,
+				})
+			}
+		}
+
+		if repair && len(drifts) > 0 {
+			if err := store.repairBalances(ctx, drifts); err != nil {
+				return err
+			}
+		}
+
+		report.AccountsScanned += int64(len(accounts))
+		report.BalanceDrifts = append(report.BalanceDrifts, drifts...)
+
+		if len(accounts) < int(batchSize) {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// repairBalances sets the balance of the drifting accounts to the sum of their entries.
+// Each account is locked and summed again first, so that a transfer committed since the scan is not undone.
+func (store *SQLStore) repairBalances(ctx context.Context, drifts []BalanceDrift) error {
+	return store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		// Reset on retries
+		for i := range drifts {
+			drifts[i].Repaired = false
+		}
+
+		// Drifts are sorted by account ID, the order TransferTx locks accounts in
+		for i := range drifts {
+			account, err := q.GetAccountForUpdate(ctx, drifts[i].AccountID)
+			if err != nil {
+				return err
+			}
+
+			entriesBalance, err := q.SumAccountEntries(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+
+			// The balance may have been corrected by someone else since the scan
+			if account.Balance != entriesBalance {
+				_, err = q.UpdateAccount(ctx, UpdateAccountParams{ID: account.ID, Balance: entriesBalance})
+				if err != nil {
+					return err
+				}
+			}
+			drifts[i].Repaired = true
+		}
+		return nil
+	})
+}
+
+func (store *SQLStore) reconcileTransfers(ctx context.Context, report *ReconcileReport, batchSize int32) error {
+	var afterID int64
+	for {
+		transfers, err := store.ListTransferEntryCounts(ctx, ListTransferEntryCountsParams{
+			AfterID: afterID,
+			Limit:   batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			if transfer.EntryCount != 2 || transfer.DebitCount != 1 || transfer.CreditCount != 1 {
+				report.TransferMismatches = append(report.TransferMismatches, TransferMismatch{
+					TransferID:  transfer.ID,
+					EntryCount:  transfer.EntryCount,
+					DebitCount:  transfer.DebitCount,
+					CreditCount: transfer.CreditCount,
+				})
+			}
+		}
+		report.TransfersScanned += int64(len(transfers))
+
+		if len(transfers) < int(batchSize) {
+			return nil
+		}
+		afterID = transfers[len(transfers)-1].ID
+	}
+}
+
+func (store *SQLStore) findOrphanedEntries(ctx context.Context, report *ReconcileReport, batchSize int32) error {
+	var afterID int64
+	for {
+		entries, err := store.ListOrphanedEntries(ctx, ListOrphanedEntriesParams{
+			AfterID: afterID,
+			Limit:   batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		report.OrphanedEntries = append(report.OrphanedEntries, entries...)
+
+		if len(entries) < int(batchSize) {
+			return nil
+		}
+		afterID = entries[len(entries)-1].ID
+	}
+}
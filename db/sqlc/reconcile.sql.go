@@ -0,0 +1,179 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: reconcile.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listAccountBalances = `-- name: ListAccountBalances :many
+SELECT a.id,
+    a.owner,
+    a.currency,
+    a.balance,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_balance
+FROM accounts a
+    LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > $1
+GROUP BY a.id
+ORDER BY a.id
+LIMIT $2
+`
+
+type ListAccountBalancesParams struct {
+	AfterID int64 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+type ListAccountBalancesRow struct {
+	ID             int64  `json:"id"`
+	Owner          string `json:"owner"`
+	Currency       string `json:"currency"`
+	Balance        int64  `json:"balance"`
+	EntriesBalance int64  `json:"entries_balance"`
+}
+
+// Balance of a batch of accounts next to the sum of their entries
+func (q *Queries) ListAccountBalances(ctx context.Context, arg ListAccountBalancesParams) ([]ListAccountBalancesRow, error) {
+	rows, err := q.db.Query(ctx, listAccountBalances, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountBalancesRow{}
+	for rows.Next() {
+		var i ListAccountBalancesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Currency,
+			&i.Balance,
+			&i.EntriesBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanedEntries = `-- name: ListOrphanedEntries :many
+SELECT id, account_id, amount, created_at, kind, memo, transfer_id, balance_after
+FROM entries
+WHERE kind = 'transfer'
+    AND transfer_id IS NULL
+    AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListOrphanedEntriesParams struct {
+	AfterID int64 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+// Transfer entries that are not linked to any transfer
+func (q *Queries) ListOrphanedEntries(ctx context.Context, arg ListOrphanedEntriesParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listOrphanedEntries, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Kind,
+			&i.Memo,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransferEntryCounts = `-- name: ListTransferEntryCounts :many
+SELECT t.id,
+    COUNT(e.id) AS entry_count,
+    COUNT(e.id) FILTER (
+        WHERE e.account_id = t.from_account_id
+            AND e.amount = - t.amount
+    ) AS debit_count,
+    COUNT(e.id) FILTER (
+        WHERE e.account_id = t.to_account_id
+            AND e.amount = t.to_amount
+    ) AS credit_count
+FROM transfers t
+    LEFT JOIN entries e ON e.transfer_id = t.id
+WHERE t.id > $1
+GROUP BY t.id
+ORDER BY t.id
+LIMIT $2
+`
+
+type ListTransferEntryCountsParams struct {
+	AfterID int64 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+type ListTransferEntryCountsRow struct {
+	ID          int64 `json:"id"`
+	EntryCount  int64 `json:"entry_count"`
+	DebitCount  int64 `json:"debit_count"`
+	CreditCount int64 `json:"credit_count"`
+}
+
+// Entries of a batch of transfers, counting those matching each leg
+func (q *Queries) ListTransferEntryCounts(ctx context.Context, arg ListTransferEntryCountsParams) ([]ListTransferEntryCountsRow, error) {
+	rows, err := q.db.Query(ctx, listTransferEntryCounts, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTransferEntryCountsRow{}
+	for rows.Next() {
+		var i ListTransferEntryCountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EntryCount,
+			&i.DebitCount,
+			&i.CreditCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumAccountEntries = `-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = $1
+`
+
+func (q *Queries) SumAccountEntries(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, sumAccountEntries, accountID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	store := NewStore(testConnPool)
+	ctx := context.Background()
+
+	// Opened with a balance but no entries
+	drifting := createRandomAccountWithBalance(t, 100)
+
+	// Funded through a deposit, consistent
+	consistent := createRandomAccountWithBalance(t, 0)
+	_, err := store.CashTx(ctx, CashTxParams{AccountID: consistent.ID, Kind: EntryKindDeposit, Amount: 50})
+	require.NoError(t, err)
+
+	// A transfer entry without its transfer
+	orphan, err := testQueries.CreateEntry(ctx, CreateEntryParams{
+		AccountID: consistent.ID,
+		Amount:    0,
+		Kind:      EntryKindTransfer,
+	})
+	require.NoError(t, err)
+
+	// A transfer without its entries
+	transfer, err := testQueries.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: consistent.ID,
+		ToAccountID:   drifting.ID,
+		Amount:        10,
+		ToAmount:      10,
+	})
+	require.NoError(t, err)
+
+	report, err := store.Reconcile(ctx, ReconcileParams{BatchSize: 2})
+	require.NoError(t, err)
+	require.False(t, report.Consistent())
+	require.NotZero(t, report.AccountsScanned)
+	require.NotZero(t, report.TransfersScanned)
+	require.False(t, report.FinishedAt.Before(report.StartedAt))
+
+	drift := findDrift(report, drifting.ID)
+	require.NotNil(t, drift)
+	require.Equal(t, int64(100), drift.Balance)
+	require.Equal(t, int64(0), drift.EntriesBalance)
+	require.Equal(t, int64(100), drift.Drift)
+	require.False(t, drift.Repaired)
+	require.Nil(t, findDrift(report, consistent.ID))
+
+	var foundOrphan bool
+	for _, entry := range report.OrphanedEntries {
+		foundOrphan = foundOrphan || entry.ID == orphan.ID
+	}
+	require.True(t, foundOrphan)
+
+	var mismatch *TransferMismatch
+	for i := range report.TransferMismatches {
+		if report.TransferMismatches[i].TransferID == transfer.ID {
+			mismatch = &report.TransferMismatches[i]
+		}
+	}
+	require.NotNil(t, mismatch)
+	require.Zero(t, mismatch.EntryCount)
+
+	// Without repair nothing changed
+	account, err := testQueries.GetAccount(ctx, drifting.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+
+	report, err = store.Reconcile(ctx, ReconcileParams{BatchSize: 2, Repair: true})
+	require.NoError(t, err)
+
+	drift = findDrift(report, drifting.ID)
+	require.NotNil(t, drift)
+	require.True(t, drift.Repaired)
+
+	account, err = testQueries.GetAccount(ctx, drifting.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), account.Balance)
+
+	// Once repaired, the balance no longer drifts
+	report, err = store.Reconcile(ctx, ReconcileParams{})
+	require.NoError(t, err)
+	require.Nil(t, findDrift(report, drifting.ID))
+}
+
+func findDrift(report ReconcileReport, accountID int64) *BalanceDrift {
+	for i := range report.BalanceDrifts {
+		if report.BalanceDrifts[i].AccountID == accountID {
+			return &report.BalanceDrifts[i]
+		}
+	}
+	return nil
+}
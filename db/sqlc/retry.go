@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how execTx re-runs transactions that failed
+// with a serialization failure or a deadlock
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt, doubled on every retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between two attempts
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy used by NewStore
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+}
+
+// RetryHook is called before a transaction is retried, with the number of the
+// attempt that just failed and its error
+type RetryHook func(ctx context.Context, attempt int, err error)
+
+// RollbackHook is called after a transaction is rolled back, with the error that caused it.
+// Transactions that are retried are rolled back before each retry.
+type RollbackHook func(ctx context.Context, err error)
+
+// StoreOption configures optional behavior of a SQLStore
+type StoreOption func(*SQLStore)
+
+// WithRetryPolicy overrides the default transaction retry policy
+func WithRetryPolicy(policy RetryPolicy) StoreOption {
+	return func(store *SQLStore) {
+		store.retryPolicy = policy
+	}
+}
+
+// WithRetryHook registers a hook that is called on every transaction retry
+func WithRetryHook(hook RetryHook) StoreOption {
+	return func(store *SQLStore) {
+		store.retryHook = hook
+	}
+}
+
+// WithRollbackHook registers a hook that is called on every transaction rollback
+func WithRollbackHook(hook RollbackHook) StoreOption {
+	return func(store *SQLStore) {
+		store.rollbackHook = hook
+	}
+}
+
+// backoff returns the delay before the attempt following the given one,
+// using exponential backoff with full jitter
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := policy.BaseDelay << shift; d > 0 && d < delay {
+			delay = d
+		}
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// #nosec G404 -- jitter does not need a cryptographically secure source
+	return time.Duration(rand.Int64N(int64(delay) + 1))
+}
+
+// isRetryable reports whether a failed transaction may succeed if run again
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlockDetected)
+}
+
+// sleep waits for the given duration or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
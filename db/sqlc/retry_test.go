@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    100 * time.Millisecond,
+	}
+
+	for attempt := 1; attempt <= 64; attempt++ {
+		limit := policy.MaxDelay
+		if attempt <= 4 {
+			limit = policy.BaseDelay << (attempt - 1)
+		}
+
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(attempt)
+			require.GreaterOrEqual(t, delay, time.Duration(0))
+			require.LessOrEqual(t, delay, limit)
+		}
+	}
+
+	require.Zero(t, RetryPolicy{}.backoff(1))
+}
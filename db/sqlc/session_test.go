@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T) Session {
+	user := createRandomUser(t)
+
+	arg := CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.RefreshToken, session.RefreshToken)
+	require.Equal(t, arg.UserAgent, session.UserAgent)
+	require.Equal(t, arg.ClientIp, session.ClientIp)
+	require.False(t, session.IsBlocked)
+	require.WithinDuration(t, arg.ExpiresAt, session.ExpiresAt, time.Second)
+	require.NotZero(t, session.CreatedAt)
+
+	return session
+}
+
+func TestCreateSession(t *testing.T) {
+	createRandomSession(t)
+}
+
+func TestGetSession(t *testing.T) {
+	newSession := createRandomSession(t)
+
+	retrievedSession, err := testQueries.GetSession(context.Background(), newSession.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, newSession.ID, retrievedSession.ID)
+	require.Equal(t, newSession.Username, retrievedSession.Username)
+	require.Equal(t, newSession.RefreshToken, retrievedSession.RefreshToken)
+}
+
+func TestBlockSession(t *testing.T) {
+	newSession := createRandomSession(t)
+
+	blockedSession, err := testQueries.BlockSession(context.Background(), newSession.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, newSession.ID, blockedSession.ID)
+	require.True(t, blockedSession.IsBlocked)
+}
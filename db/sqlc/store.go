@@ -2,54 +2,233 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
 
+	"github.com/aronreisx/bubblebank/logging"
+	"github.com/aronreisx/bubblebank/numeric"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// ErrInsufficientFunds is returned when a transfer would overdraw the sending account
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrSameAccount is returned when a transfer has the same source and destination account
+	ErrSameAccount = errors.New("cannot transfer to the same account")
+	// ErrInvalidAmount is returned when a transfer amount is not positive
+	ErrInvalidAmount = errors.New("transfer amount must be positive")
+	// ErrTransferAlreadyReversed is returned when a transfer has already been fully refunded
+	ErrTransferAlreadyReversed = errors.New("transfer already fully reversed")
+	// ErrRefundExceedsRemainder is returned when a refund is larger than what is left to refund
+	ErrRefundExceedsRemainder = errors.New("refund exceeds the amount left to refund")
+	// ErrReverseReversal is returned when reversing a transfer that is itself a reversal
+	ErrReverseReversal = errors.New("cannot reverse a reversal")
+	// ErrHoldNotActive is returned when capturing or releasing a hold that was already closed or expired
+	ErrHoldNotActive = errors.New("hold is not active")
+	// ErrCaptureExceedsHold is returned when capturing more than the amount of a hold
+	ErrCaptureExceedsHold = errors.New("capture exceeds the held amount")
+	// ErrInvalidHoldExpiry is returned when placing a hold that expires in the past
+	ErrInvalidHoldExpiry = errors.New("hold must expire in the future")
+	// ErrCurrencyMismatch is returned when moving money between accounts of different currencies without a rate
+	ErrCurrencyMismatch = errors.New("accounts have different currencies")
+	// ErrAccountNotActive is returned when moving money in or out of a frozen or closed account
+	ErrAccountNotActive = errors.New("account is not active")
+	// ErrInvalidStatusTransition is returned when an account cannot move to the requested status
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	// ErrAccountNotEmpty is returned when closing an account that still holds money
+	ErrAccountNotEmpty = errors.New("account balance must be zero to close it")
+	// ErrWithdrawalLimitExceeded is returned when a withdrawal would exceed the account's daily limit
+	ErrWithdrawalLimitExceeded = errors.New("daily withdrawal limit exceeded")
+	// ErrDailyDepositLimitExceeded is returned when a deposit would exceed the account's daily limit
+	ErrDailyDepositLimitExceeded = errors.New("daily deposit limit exceeded")
+)
+
+// SystemAccountOwner owns the settlement account of each currency.
+// Usernames must be alphanumeric, so that no customer can take it.
+const SystemAccountOwner = "_system"
+
+// Kinds of ledger entries
+const (
+	EntryKindTransfer   = "transfer"
+	EntryKindDeposit    = "deposit"
+	EntryKindWithdrawal = "withdrawal"
 )
 
 // Store defines all functions to execute db queries and transactions
 type Store interface {
 	Querier
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error)
+	UpdateAccountStatusTx(ctx context.Context, arg UpdateAccountStatusTxParams) (Account, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (TransferTxResult, error)
+	CashTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (HoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error)
+	ExpireHoldsTx(ctx context.Context) (int64, error)
+	Reconcile(ctx context.Context, arg ReconcileParams) (ReconcileReport, error)
+	Ping(ctx context.Context) error
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
 type SQLStore struct {
-	connPool *pgxpool.Pool
+	connPool     *pgxpool.Pool
+	retryHook    RetryHook
+	rollbackHook RollbackHook
+	retryPolicy  RetryPolicy
 	*Queries
 }
 
 // NewStore creates a new store
-func NewStore(connPool *pgxpool.Pool) Store {
-	return &SQLStore{
-		connPool: connPool,
-		Queries:  New(connPool),
+func NewStore(connPool *pgxpool.Pool, opts ...StoreOption) Store {
+	store := &SQLStore{
+		connPool:    connPool,
+		retryPolicy: DefaultRetryPolicy,
+		Queries:     New(translatingDBTX{connPool}),
+	}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	return store
+}
+
+// Ping checks that the database can be reached, acquiring a connection from the pool
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return translateError(store.connPool.Ping(ctx))
+}
+
+// execTx executes a function within a database transaction.
+// Transactions failing with a serialization failure or a deadlock are re-run
+// from scratch according to the store's retry policy, so fn must be safe to call more than once.
+func (store *SQLStore) execTx(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) (err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "execTx")
+	defer func() { endSpan(span, err) }()
+
+	for attempt := 1; ; attempt++ {
+		err := store.runTx(ctx, txOptions, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		if attempt >= store.retryPolicy.MaxAttempts {
+			return fmt.Errorf("transaction failed after %d attempts: %w", attempt, err)
+		}
+
+		logging.FromContext(ctx).Warn("retrying transaction",
+			slog.Int("attempt", attempt),
+			slog.String("code", ErrorCode(err)),
+			slog.Any("error", err),
+		)
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("error", err.Error()),
+		))
+		if store.retryHook != nil {
+			store.retryHook(ctx, attempt, err)
+		}
+
+		if err := sleep(ctx, store.retryPolicy.backoff(attempt)); err != nil {
+			return err
+		}
 	}
 }
 
-// execTx executes a function within a database transaction
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.connPool.Begin(ctx)
+// runTx executes a function within a single database transaction.
+// The transaction is rolled back if fn returns an error or panics.
+func (store *SQLStore) runTx(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.connPool.BeginTx(ctx, txOptions)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
-	q := New(tx)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			store.rolledBack(ctx, fmt.Errorf("panic: %v", p))
+			panic(p)
+		}
+	}()
+
+	q := New(spanDBTX{DBTX: translatingDBTX{tx}, span: trace.SpanFromContext(ctx)})
 	err = fn(q)
 	if err != nil {
-		/* if rbErr := tx.Rollback(ctx); rbErr != nil {
-			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
-		} */
+		// Roll back even if ctx was canceled, otherwise the connection stays busy until pgx closes it
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			err = fmt.Errorf("tx err: %w, rb err: %w", err, rbErr)
+		}
+		store.rolledBack(ctx, err)
 		return err
 	}
 
-	return tx.Commit(ctx)
+	// A failed commit, such as a serialization failure, rolls the transaction back too
+	if err := translateError(tx.Commit(ctx)); err != nil {
+		store.rolledBack(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// rolledBack reports a rolled back transaction to the rollback hook, if any
+func (store *SQLStore) rolledBack(ctx context.Context, err error) {
+	if store.rollbackHook != nil {
+		store.rollbackHook(ctx, err)
+	}
+}
+
+// CreateAccountTxParams contains the input parameters of the create account transaction
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	// AfterCreate, if set, runs inside the transaction once the account is created.
+	// Returning an error rolls the account back.
+	AfterCreate func(q Querier, account Account) error `json:"-"`
+}
+
+// CreateAccountTx creates an account and runs arg.AfterCreate within a single database transaction
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		var err error
+
+		account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, account)
+		}
+		return nil
+	})
+
+	return account, err
 }
 
 // TransferTxParams contains the input parameters of the transfer transaction
 type TransferTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	// AfterTransfer, if set, runs inside the transaction once the transfer is done.
+	// Returning an error rolls the transfer back.
+	AfterTransfer func(q Querier, result TransferTxResult) error `json:"-"`
+	// Rate and RateAt record the exchange rate a cross-currency transfer was converted with
+	Rate          pgtype.Numeric     `json:"rate"`
+	RateAt        pgtype.Timestamptz `json:"rate_at"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	// Amount is debited from the source account, in its currency
+	Amount int64 `json:"amount"`
+	// ToAmount is credited to the destination account, in its currency.
+	// It defaults to Amount when both accounts share a currency.
+	ToAmount int64 `json:"to_amount"`
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -66,48 +245,397 @@ type TransferTxResult struct {
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
+	if arg.Amount <= 0 || arg.ToAmount < 0 {
+		return result, ErrInvalidAmount
+	}
+
+	toAmount := arg.ToAmount
+	if toAmount == 0 {
+		toAmount = arg.Amount
+	}
+
+	if arg.FromAccountID == arg.ToAccountID {
+		return result, ErrSameAccount
+	}
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
 		var err error
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams(arg))
+		result, err = postTransfer(ctx, q, CreateTransferParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			ToAmount:      toAmount,
+			Rate:          arg.Rate,
+			RateAt:        arg.RateAt,
+		}, "")
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		if arg.AfterTransfer != nil {
+			return arg.AfterTransfer(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	// AfterReverse, if set, runs inside the transaction once the reversal is done.
+	// Returning an error rolls the reversal back.
+	AfterReverse func(q Querier, result TransferTxResult) error `json:"-"`
+	TransferID   int64                                          `json:"transfer_id"`
+	// Amount is moved back from the destination account of the transfer, in its currency.
+	// Zero reverses whatever has not been refunded yet.
+	Amount int64 `json:"amount"`
+}
+
+// ReverseTransferTx creates a compensating transfer moving money back from the
+// destination account of a transfer to its source account. A transfer may be
+// refunded in several parts, up to the amount it credited.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	if arg.Amount < 0 {
+		return result, ErrInvalidAmount
+	}
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		// Lock the original, so that concurrent refunds cannot exceed it together
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+		if original.ReversalOf.Valid {
+			return ErrReverseReversal
+		}
+
+		refunded, err := q.SumTransferReversals(ctx, pgtype.Int8{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		// The reversal debits what the original credited and the other way around
+		remaining := original.ToAmount - refunded.Amount
+		if remaining <= 0 {
+			return ErrTransferAlreadyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return fmt.Errorf("%w: %d left to refund", ErrRefundExceedsRemainder, remaining)
+		}
+
+		// Credit back the share of the original amount, the last refund takes whatever is left,
+		// so that rounding never leaves a cross-currency transfer partly refunded
+		toAmount := original.Amount - refunded.ToAmount
+		if amount < remaining {
+			toAmount = proportion(original.Amount, amount, original.ToAmount)
+		}
+		if toAmount <= 0 {
+			return ErrInvalidAmount
+		}
+
+		params := CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        amount,
+			ToAmount:      toAmount,
+			ReversalOf:    pgtype.Int8{Int64: original.ID, Valid: true},
+		}
+		if original.Rate.Valid {
+			params.Rate, err = inverseRate(original.Rate)
+			if err != nil {
+				return err
+			}
+			params.RateAt = original.RateAt
+		}
+
+		result, err = postTransfer(ctx, q, params, fmt.Sprintf("reversal of transfer %d", original.ID))
 		if err != nil {
 			return err
 		}
 
-		result.FromAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-			ID:     arg.FromAccountID,
-			Amount: -arg.Amount,
+		if arg.AfterReverse != nil {
+			return arg.AfterReverse(q, result)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// postTransfer moves money between two accounts: it creates the transfer record,
+// adds both account entries, with the balances they leave, and updates both balances.
+// It must run inside a transaction.
+func postTransfer(ctx context.Context, q *Queries, arg CreateTransferParams, memo string) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	// Lock both accounts in ascending ID order, so that two concurrent
+	// transfers in opposite directions always wait on the same row first
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	if err := requireActive(fromAccount, toAccount); err != nil {
+		return result, err
+	}
+
+	fromAccount, err = releaseExpiredHolds(ctx, q, fromAccount)
+	if err != nil {
+		return result, err
+	}
+
+	if fromAccount.AvailableBalance() < arg.Amount {
+		return result, ErrInsufficientFunds
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    arg.FromAccountID,
+		Amount:       -arg.Amount,
+		Kind:         EntryKindTransfer,
+		Memo:         memo,
+		TransferID:   pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		BalanceAfter: fromAccount.Balance - arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    arg.ToAccountID,
+		Amount:       arg.ToAmount,
+		Kind:         EntryKindTransfer,
+		Memo:         memo,
+		TransferID:   pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		BalanceAfter: toAccount.Balance + arg.ToAmount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if fromAccount.ID < toAccount.ID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.ToAmount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.ToAmount, arg.FromAccountID, -arg.Amount)
+	}
+	return result, err
+}
+
+// proportion returns a * b / c rounded down, without overflowing int64 in between
+func proportion(a, b, c int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	return product.Quo(product, big.NewInt(c)).Int64()
+}
+
+// inverseRate returns 1 / rate with the decimals of the rate columns
+func inverseRate(rate pgtype.Numeric) (pgtype.Numeric, error) {
+	value, err := numeric.ToRat(rate)
+	if err != nil {
+		return pgtype.Numeric{}, err
+	}
+	if value.Sign() == 0 {
+		return pgtype.Numeric{}, errors.New("cannot invert a zero rate")
+	}
+
+	return numeric.FromRat(value.Inv(value), numeric.RateScale)
+}
+
+// CashTxParams contains the input parameters of the cash transaction
+type CashTxParams struct {
+	// AfterPost, if set, runs inside the transaction once the entries are posted.
+	// Returning an error rolls the deposit or withdrawal back.
+	AfterPost func(q Querier, result CashTxResult) error `json:"-"`
+	// Kind is either EntryKindDeposit or EntryKindWithdrawal
+	Kind string `json:"kind"`
+	// Memo is stored on both entries
+	Memo      string `json:"memo"`
+	AccountID int64  `json:"account_id"`
+	// Amount is credited to the account by a deposit and debited from it by a withdrawal
+	Amount int64 `json:"amount"`
+	// DailyLimit caps the deposits or the withdrawals, according to Kind, of the account
+	// over the last 24 hours, zero means no limit
+	DailyLimit int64 `json:"daily_limit"`
+}
+
+// CashTxResult is the result of the cash transaction
+type CashTxResult struct {
+	Account       Account `json:"account"`
+	SystemAccount Account `json:"system_account"`
+	Entry         Entry   `json:"entry"`
+	SystemEntry   Entry   `json:"system_entry"`
+}
+
+// CashTx deposits money to or withdraws money from an account.
+// The other leg is posted to the settlement account of the account's currency,
+// so that the ledger stays balanced, within a single database transaction.
+func (store *SQLStore) CashTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	var result CashTxResult
+
+	if arg.Amount <= 0 {
+		return result, ErrInvalidAmount
+	}
+
+	var amount int64
+	switch arg.Kind {
+	case EntryKindDeposit:
+		amount = arg.Amount
+	case EntryKindWithdrawal:
+		amount = -arg.Amount
+	default:
+		return result, fmt.Errorf("invalid cash entry kind %q", arg.Kind)
+	}
+
+	err := store.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		systemAccount, err := q.GetSystemAccount(ctx, account.Currency)
+		if err != nil {
+			return fmt.Errorf("cannot find %s settlement account: %w", account.Currency, err)
+		}
+
+		if account.ID == systemAccount.ID {
+			return ErrSameAccount
+		}
+
+		account, systemAccount, err = lockAccounts(ctx, q, account.ID, systemAccount.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := requireActive(account); err != nil {
+			return err
+		}
+
+		if arg.Kind == EntryKindWithdrawal {
+			account, err = releaseExpiredHolds(ctx, q, account)
+			if err != nil {
+				return err
+			}
+
+			if account.AvailableBalance() < arg.Amount {
+				return ErrInsufficientFunds
+			}
+		}
+
+		if arg.DailyLimit > 0 {
+			total, err := q.SumAccountEntriesSince(ctx, SumAccountEntriesSinceParams{
+				AccountID: account.ID,
+				Kind:      arg.Kind,
+				Since:     time.Now().Add(-24 * time.Hour),
+			})
+			if err != nil {
+				return err
+			}
+
+			// Withdrawal entries are negative
+			if arg.Kind == EntryKindWithdrawal {
+				total = -total
+			}
+			if arg.Amount+total > arg.DailyLimit {
+				if arg.Kind == EntryKindWithdrawal {
+					return ErrWithdrawalLimitExceeded
+				}
+				return ErrDailyDepositLimitExceeded
+			}
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:    account.ID,
+			Amount:       amount,
+			Kind:         arg.Kind,
+			Memo:         arg.Memo,
+			BalanceAfter: account.Balance + amount,
 		})
 		if err != nil {
 			return err
 		}
 
-		result.ToAccount, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-			ID:     arg.ToAccountID,
-			Amount: arg.Amount,
+		result.SystemEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:    systemAccount.ID,
+			Amount:       -amount,
+			Kind:         arg.Kind,
+			Memo:         arg.Memo,
+			BalanceAfter: systemAccount.Balance - amount,
 		})
 		if err != nil {
 			return err
 		}
 
+		if account.ID < systemAccount.ID {
+			result.Account, result.SystemAccount, err = addMoney(ctx, q, account.ID, amount, systemAccount.ID, -amount)
+		} else {
+			result.SystemAccount, result.Account, err = addMoney(ctx, q, systemAccount.ID, -amount, account.ID, amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterPost != nil {
+			return arg.AfterPost(q, result)
+		}
 		return nil
 	})
 
 	return result, err
 }
+
+// lockAccounts acquires row locks on both accounts in ascending ID order
+// and returns them in the order they were requested
+func lockAccounts(ctx context.Context, q *Queries, fromAccountID, toAccountID int64) (fromAccount, toAccount Account, err error) {
+	if fromAccountID < toAccountID {
+		fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+		if err != nil {
+			return
+		}
+		toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+		return
+	}
+
+	toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+	if err != nil {
+		return
+	}
+	fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+	return
+}
+
+// addMoney updates the balance of two accounts, in the order they are given
+func addMoney(
+	ctx context.Context,
+	q *Queries,
+	accountID1 int64,
+	amount1 int64,
+	accountID2 int64,
+	amount2 int64,
+) (account1 Account, account2 Account, err error) {
+	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     accountID1,
+		Amount: amount1,
+	})
+	if err != nil {
+		return
+	}
+
+	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     accountID2,
+		Amount: amount2,
+	})
+	return
+}
@@ -2,21 +2,38 @@ package db
 
 import (
 	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
-func TestTransferTx(t *testing.T) {
+func TestPing(t *testing.T) {
 	store := NewStore(testConnPool)
+	require.NoError(t, store.Ping(context.Background()))
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, store.Ping(ctx))
+}
+
+func TestTransferTx(t *testing.T) {
+	store := NewStore(testConnPool)
 
 	// Run n concurrent transfer transactions
 	n := 5
 	amount := int64(10)
 
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount+util.RandomMoney())
+	account2 := createRandomAccount(t)
+
 	errs := make(chan error)
 	results := make(chan TransferTxResult)
 
@@ -49,6 +66,8 @@ func TestTransferTx(t *testing.T) {
 		require.Equal(t, account1.ID, transfer.FromAccountID)
 		require.Equal(t, account2.ID, transfer.ToAccountID)
 		require.Equal(t, amount, transfer.Amount)
+		require.Equal(t, amount, transfer.ToAmount)
+		require.False(t, transfer.Rate.Valid)
 		require.NotZero(t, transfer.ID)
 		require.NotZero(t, transfer.CreatedAt)
 
@@ -60,6 +79,8 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, fromEntry)
 		require.Equal(t, account1.ID, fromEntry.AccountID)
 		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, EntryKindTransfer, fromEntry.Kind)
+		require.Equal(t, transfer.ID, fromEntry.TransferID.Int64)
 		require.NotZero(t, fromEntry.ID)
 		require.NotZero(t, fromEntry.CreatedAt)
 
@@ -109,3 +130,654 @@ func TestTransferTx(t *testing.T) {
 	require.Equal(t, account1.Balance-int64(n)*amount, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance+int64(n)*amount, updatedAccount2.Balance)
 }
+
+func TestTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	// Run n concurrent transfer transactions, half of them in each direction
+	n := 10
+	amount := int64(10)
+
+	account1 := createRandomAccountWithBalance(t, int64(n)*amount)
+	account2 := createRandomAccountWithBalance(t, int64(n)*amount)
+
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccountID := account1.ID
+		toAccountID := account2.ID
+
+		if i%2 == 1 {
+			fromAccountID = account2.ID
+			toAccountID = account1.ID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	// Both directions moved the same total, so balances must be unchanged
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1 := createRandomAccountWithBalance(t, 10)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Empty(t, result.Transfer)
+
+	// Nothing must have been written
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account1.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestTransferTxInvalidParams(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrSameAccount)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        0,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        -10,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ToAmount:      -10,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestTransferTxAfterTransfer(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccount(t)
+
+	// An AfterTransfer error rolls the whole transfer back
+	hookErr := errors.New("after transfer failed")
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		AfterTransfer: func(_ Querier, result TransferTxResult) error {
+			require.NotZero(t, result.Transfer.ID)
+			return hookErr
+		},
+	})
+	require.ErrorIs(t, err, hookErr)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	// A successful AfterTransfer sees the transfer and commits with it
+	var seen TransferTxResult
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		AfterTransfer: func(_ Querier, result TransferTxResult) error {
+			seen = result
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, result, seen)
+	require.Equal(t, account1.Balance-10, result.FromAccount.Balance)
+}
+
+func TestTransferTxCrossCurrency(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	createAccount := func(currency string, balance int64) Account {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  balance,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		return account
+	}
+
+	account1 := createAccount("USD", 1000)
+	account2 := createAccount("JPY", 0)
+
+	var rate pgtype.Numeric
+	require.NoError(t, rate.Scan("150.25"))
+	rateAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+		ToAmount:      1502,
+		Rate:          rate,
+		RateAt:        pgtype.Timestamptz{Time: rateAt, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1000), result.Transfer.Amount)
+	require.Equal(t, int64(1502), result.Transfer.ToAmount)
+	require.True(t, result.Transfer.Rate.Valid)
+	require.True(t, result.Transfer.RateAt.Time.Equal(rateAt))
+
+	require.Equal(t, int64(-1000), result.FromEntry.Amount)
+	require.Equal(t, int64(1502), result.ToEntry.Amount)
+	require.Equal(t, result.FromAccount.Balance, result.FromEntry.BalanceAfter)
+	require.Equal(t, result.ToAccount.Balance, result.ToEntry.BalanceAfter)
+	require.Equal(t, int64(0), result.FromAccount.Balance)
+	require.Equal(t, int64(1502), result.ToAccount.Balance)
+
+	// A rate without its timestamp breaks the transfers_rate_check constraint
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        100,
+		ToAmount:      1,
+		Rate:          rate,
+	})
+	require.Error(t, err)
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account1 := createRandomAccountWithBalance(t, 100)
+	account2 := createRandomAccountWithBalance(t, 0)
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// Partial refund
+	partial, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     30,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, account2.ID, partial.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, partial.Transfer.ToAccountID)
+	require.Equal(t, int64(30), partial.Transfer.Amount)
+	require.Equal(t, pgtype.Int8{Int64: original.Transfer.ID, Valid: true}, partial.Transfer.ReversalOf)
+
+	require.Equal(t, int64(-30), partial.FromEntry.Amount)
+	require.Equal(t, int64(30), partial.ToEntry.Amount)
+	require.Equal(t, EntryKindTransfer, partial.FromEntry.Kind)
+	require.Equal(t, pgtype.Int8{Int64: partial.Transfer.ID, Valid: true}, partial.ToEntry.TransferID)
+	require.Equal(t, int64(70), partial.FromAccount.Balance)
+	require.Equal(t, int64(30), partial.ToAccount.Balance)
+
+	// More than the remainder
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     71,
+	})
+	require.ErrorIs(t, err, ErrRefundExceedsRemainder)
+
+	// A reversal cannot be reversed itself
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: partial.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrReverseReversal)
+
+	// The remainder
+	rest, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), rest.Transfer.Amount)
+	require.Equal(t, int64(0), rest.FromAccount.Balance)
+	require.Equal(t, int64(100), rest.ToAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+
+	// Both refunds show up in the history of both accounts
+	for _, accountID := range []int64{account1.ID, account2.ID} {
+		entries, err := testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+			AccountID: accountID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+	}
+}
+
+func TestReverseTransferTxCrossCurrency(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	createAccount := func(currency string, balance int64) Account {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  balance,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		return account
+	}
+
+	account1 := createAccount("USD", 1000)
+	account2 := createAccount("JPY", 0)
+
+	var rate pgtype.Numeric
+	require.NoError(t, rate.Scan("1.5"))
+
+	original, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+		ToAmount:      1500,
+		Rate:          rate,
+		RateAt:        pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Refunds are in the currency of the destination account and credit back the matching share
+	partial, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+		Amount:     500,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), partial.Transfer.Amount)
+	require.Equal(t, int64(333), partial.Transfer.ToAmount)
+	require.True(t, partial.Transfer.Rate.Valid)
+	require.True(t, partial.Transfer.RateAt.Time.Equal(original.Transfer.RateAt.Time))
+
+	// The last refund gives back exactly what is left of the original amount
+	rest, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), rest.Transfer.Amount)
+	require.Equal(t, int64(667), rest.Transfer.ToAmount)
+	require.Equal(t, int64(1000), rest.ToAccount.Balance)
+	require.Equal(t, int64(0), rest.FromAccount.Balance)
+}
+
+func TestReverseTransferTxInvalidParams(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: 1,
+		Amount:     -1,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: math.MaxInt64,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCashTx(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccountWithBalance(t, 0)
+
+	// Deposit
+	deposit, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Kind:      EntryKindDeposit,
+		Amount:    100,
+		Memo:      "cash at branch",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(100), deposit.Account.Balance)
+	require.Equal(t, SystemAccountOwner, deposit.SystemAccount.Owner)
+	require.Equal(t, account.Currency, deposit.SystemAccount.Currency)
+
+	require.Equal(t, account.ID, deposit.Entry.AccountID)
+	require.Equal(t, int64(100), deposit.Entry.Amount)
+	require.Equal(t, EntryKindDeposit, deposit.Entry.Kind)
+	require.Equal(t, "cash at branch", deposit.Entry.Memo)
+	require.Equal(t, deposit.Account.Balance, deposit.Entry.BalanceAfter)
+
+	require.Equal(t, deposit.SystemAccount.ID, deposit.SystemEntry.AccountID)
+	require.Equal(t, int64(-100), deposit.SystemEntry.Amount)
+	require.Equal(t, "cash at branch", deposit.SystemEntry.Memo)
+	require.Equal(t, deposit.SystemAccount.Balance, deposit.SystemEntry.BalanceAfter)
+
+	// Withdrawal
+	withdrawal, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Kind:      EntryKindWithdrawal,
+		Amount:    30,
+		Memo:      "atm",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(70), withdrawal.Account.Balance)
+	require.Equal(t, int64(-30), withdrawal.Entry.Amount)
+	require.Equal(t, int64(70), withdrawal.Entry.BalanceAfter)
+	require.Equal(t, int64(30), withdrawal.SystemEntry.Amount)
+	require.Equal(t, EntryKindWithdrawal, withdrawal.SystemEntry.Kind)
+
+	// The settlement account holds the opposite of every cash movement
+	systemAccount, err := testQueries.GetSystemAccount(context.Background(), account.Currency)
+	require.NoError(t, err)
+	require.Equal(t, deposit.SystemAccount.ID, systemAccount.ID)
+}
+
+func TestCashTxWithdrawalLimits(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccountWithBalance(t, 100)
+
+	_, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Kind:      EntryKindWithdrawal,
+		Amount:    101,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindWithdrawal,
+		Amount:     40,
+		DailyLimit: 50,
+	})
+	require.NoError(t, err)
+
+	// 40 were already withdrawn today
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindWithdrawal,
+		Amount:     11,
+		DailyLimit: 50,
+	})
+	require.ErrorIs(t, err, ErrWithdrawalLimitExceeded)
+
+	result, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindWithdrawal,
+		Amount:     10,
+		DailyLimit: 50,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), result.Account.Balance)
+}
+
+func TestCashTxDepositLimit(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccount(t)
+
+	_, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindDeposit,
+		Amount:     40,
+		DailyLimit: 50,
+	})
+	require.NoError(t, err)
+
+	// 40 were already deposited today
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindDeposit,
+		Amount:     11,
+		DailyLimit: 50,
+	})
+	require.ErrorIs(t, err, ErrDailyDepositLimitExceeded)
+
+	result, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID:  account.ID,
+		Kind:       EntryKindDeposit,
+		Amount:     10,
+		DailyLimit: 50,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+50, result.Account.Balance)
+}
+
+func TestCashTxInvalidParams(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccount(t)
+
+	_, err := store.CashTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Kind:      EntryKindDeposit,
+		Amount:    0,
+	})
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Kind:      EntryKindTransfer,
+		Amount:    10,
+	})
+	require.Error(t, err)
+
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID: -1,
+		Kind:      EntryKindDeposit,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	systemAccount, err := testQueries.GetSystemAccount(context.Background(), account.Currency)
+	require.NoError(t, err)
+
+	_, err = store.CashTx(context.Background(), CashTxParams{
+		AccountID: systemAccount.ID,
+		Kind:      EntryKindDeposit,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrSameAccount)
+}
+
+func TestCreateAccountTx(t *testing.T) {
+	store := NewStore(testConnPool)
+	user := createRandomUser(t)
+
+	arg := CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  0,
+		Currency: util.RandomCurrency(),
+	}
+
+	// An AfterCreate error rolls the account back
+	hookErr := errors.New("after create failed")
+	_, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: arg,
+		AfterCreate: func(_ Querier, _ Account) error {
+			return hookErr
+		},
+	})
+	require.ErrorIs(t, err, hookErr)
+
+	accounts, err := testQueries.ListAccounts(context.Background(), ListAccountsParams{Owner: user.Username, Limit: 5})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountTxParams{CreateAccountParams: arg})
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, account.Owner)
+	require.Equal(t, arg.Currency, account.Currency)
+}
+
+func TestExecTxRollback(t *testing.T) {
+	var rollbacks []error
+	store := NewStore(testConnPool, WithRollbackHook(func(_ context.Context, err error) {
+		rollbacks = append(rollbacks, err)
+	})).(*SQLStore)
+	account := createRandomAccount(t)
+
+	fnErr := errors.New("fn failed")
+	err := store.execTx(context.Background(), pgx.TxOptions{}, func(q *Queries) error {
+		_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: 10,
+		})
+		require.NoError(t, err)
+
+		return fnErr
+	})
+	require.ErrorIs(t, err, fnErr)
+	require.Len(t, rollbacks, 1)
+	require.ErrorIs(t, rollbacks[0], fnErr)
+
+	// The balance update must have been rolled back and the connection released
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+	require.Zero(t, testConnPool.Stat().AcquiredConns())
+}
+
+func TestExecTxPanicRollback(t *testing.T) {
+	store := NewStore(testConnPool).(*SQLStore)
+	account := createRandomAccount(t)
+
+	require.Panics(t, func() {
+		_ = store.execTx(context.Background(), pgx.TxOptions{}, func(q *Queries) error {
+			_, err := q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+				ID:     account.ID,
+				Amount: 10,
+			})
+			require.NoError(t, err)
+
+			panic("fn panicked")
+		})
+	})
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+	require.Zero(t, testConnPool.Stat().AcquiredConns())
+}
+
+func TestExecTxRetrySerializable(t *testing.T) {
+	var retries atomic.Int32
+
+	store := NewStore(testConnPool,
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 10,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    20 * time.Millisecond,
+		}),
+		WithRetryHook(func(_ context.Context, _ int, _ error) {
+			retries.Add(1)
+		}),
+	).(*SQLStore)
+
+	account := createRandomAccountWithBalance(t, 0)
+
+	// Keep n below the pool size, every transaction holds a connection while waiting on the others
+	n := 3
+	errs := make(chan error)
+
+	var allRead sync.WaitGroup
+	allRead.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			firstAttempt := true
+
+			errs <- store.execTx(context.Background(), pgx.TxOptions{IsoLevel: pgx.Serializable}, func(q *Queries) error {
+				current, err := q.GetAccount(context.Background(), account.ID)
+				if err != nil {
+					return err
+				}
+
+				// Make sure every first attempt reads the row before any of them writes it,
+				// so all but one are forced into a serialization failure
+				if firstAttempt {
+					firstAttempt = false
+					allRead.Done()
+					allRead.Wait()
+				}
+
+				_, err = q.UpdateAccount(context.Background(), UpdateAccountParams{
+					ID:      account.ID,
+					Balance: current.Balance + 1,
+				})
+				return err
+			})
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(n), updatedAccount.Balance)
+	require.GreaterOrEqual(t, retries.Load(), int32(n-1))
+}
+
+func TestExecTxRetryExhausted(t *testing.T) {
+	store := NewStore(testConnPool, WithRetryPolicy(RetryPolicy{MaxAttempts: 3})).(*SQLStore)
+
+	attempts := 0
+	err := store.execTx(context.Background(), pgx.TxOptions{}, func(_ *Queries) error {
+		attempts++
+		return &Error{Kind: ErrDeadlockDetected, Err: errors.New("forced deadlock")}
+	})
+	require.ErrorIs(t, err, ErrDeadlockDetected)
+	require.Equal(t, 3, attempts)
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans of the store
+const tracerName = "github.com/aronreisx/bubblebank/db"
+
+// spanDBTX runs queries as children of the span of their transaction.
+// Store transactions issue their queries with the context of their caller,
+// which does not carry the transaction's span.
+type spanDBTX struct {
+	DBTX
+	span trace.Span
+}
+
+func (db spanDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return db.DBTX.Exec(trace.ContextWithSpan(ctx, db.span), sql, args...)
+}
+
+func (db spanDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return db.DBTX.Query(trace.ContextWithSpan(ctx, db.span), sql, args...)
+}
+
+func (db spanDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return db.DBTX.QueryRow(trace.ContextWithSpan(ctx, db.span), sql, args...)
+}
+
+// endSpan ends a span, marking it as failed when err is set
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -7,26 +7,44 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
         from_account_id,
         to_account_id,
-        amount
+        amount,
+        to_amount,
+        rate,
+        rate_at,
+        reversal_of
     )
-VALUES ($1, $2, $3)
-RETURNING id, from_account_id, to_account_id, amount, created_at
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, rate, rate_at, reversal_of
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        int64              `json:"amount"`
+	ToAmount      int64              `json:"to_amount"`
+	Rate          pgtype.Numeric     `json:"rate"`
+	RateAt        pgtype.Timestamptz `json:"rate_at"`
+	ReversalOf    pgtype.Int8        `json:"reversal_of"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.Rate,
+		arg.RateAt,
+		arg.ReversalOf,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +52,16 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.Rate,
+		&i.RateAt,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, rate, rate_at, reversal_of
 FROM transfers
 WHERE id = $1
 LIMIT 1
@@ -54,32 +76,131 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.Rate,
+		&i.RateAt,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, rate, rate_at, reversal_of
+FROM transfers
+WHERE id = $1
+LIMIT 1 FOR NO KEY
+UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.Rate,
+		&i.RateAt,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, rate, rate_at, reversal_of
 FROM transfers
-WHERE from_account_id = $1
-    OR to_account_id = $2
-ORDER BY id
-LIMIT $3 OFFSET $4
+WHERE (
+        from_account_id = $1
+        OR to_account_id = $1
+    )
+    AND (
+        $2::varchar IS NULL
+        OR (
+            $2 = 'outgoing'
+            AND from_account_id = $1
+        )
+        OR (
+            $2 = 'incoming'
+            AND to_account_id = $1
+        )
+    )
+    AND (
+        $3::bigint IS NULL
+        OR (
+            from_account_id = $1
+            AND to_account_id = $3
+        )
+        OR (
+            to_account_id = $1
+            AND from_account_id = $3
+        )
+    )
+    AND (
+        $4::bigint IS NULL
+        OR CASE
+            WHEN from_account_id = $1 THEN amount
+            ELSE to_amount
+        END >= $4
+    )
+    AND (
+        $5::bigint IS NULL
+        OR CASE
+            WHEN from_account_id = $1 THEN amount
+            ELSE to_amount
+        END <= $5
+    )
+    AND (
+        $6::timestamptz IS NULL
+        OR created_at >= $6
+    )
+    AND (
+        $7::timestamptz IS NULL
+        OR created_at < $7
+    )
+    AND (
+        $8::timestamptz IS NULL
+        OR (created_at, id) > (
+            $8,
+            $9::bigint
+        )
+    )
+ORDER BY created_at,
+    id
+LIMIT $11 OFFSET $10
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	AccountID       int64              `json:"account_id"`
+	Direction       pgtype.Text        `json:"direction"`
+	CounterpartyID  pgtype.Int8        `json:"counterparty_id"`
+	MinAmount       pgtype.Int8        `json:"min_amount"`
+	MaxAmount       pgtype.Int8        `json:"max_amount"`
+	FromTime        pgtype.Timestamptz `json:"from_time"`
+	ToTime          pgtype.Timestamptz `json:"to_time"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	Offset          int32              `json:"offset"`
+	Limit           int32              `json:"limit"`
 }
 
+// Amount filters compare the amount in the currency of the listed account,
+// to_amount when it received the transfer
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
 	rows, err := q.db.Query(ctx, listTransfers,
-		arg.FromAccountID,
-		arg.ToAccountID,
-		arg.Limit,
+		arg.AccountID,
+		arg.Direction,
+		arg.CounterpartyID,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CursorCreatedAt,
+		arg.CursorID,
 		arg.Offset,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -94,6 +215,10 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.Rate,
+			&i.RateAt,
+			&i.ReversalOf,
 		); err != nil {
 			return nil, err
 		}
@@ -104,3 +229,23 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const sumTransferReversals = `-- name: SumTransferReversals :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS amount,
+    COALESCE(SUM(to_amount), 0)::bigint AS to_amount
+FROM transfers
+WHERE reversal_of = $1
+`
+
+type SumTransferReversalsRow struct {
+	Amount   int64 `json:"amount"`
+	ToAmount int64 `json:"to_amount"`
+}
+
+// Amounts already moved back by the reversals of a transfer
+func (q *Queries) SumTransferReversals(ctx context.Context, reversalOf pgtype.Int8) (SumTransferReversalsRow, error) {
+	row := q.db.QueryRow(ctx, sumTransferReversals, reversalOf)
+	var i SumTransferReversalsRow
+	err := row.Scan(&i.Amount, &i.ToAmount)
+	return i, err
+}
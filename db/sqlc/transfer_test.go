@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTransfers(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccountWithBalance(t, 1000)
+	counterparty1 := createRandomAccountWithBalance(t, 1000)
+	counterparty2 := createRandomAccountWithBalance(t, 1000)
+
+	transfers := []TransferTxParams{
+		{FromAccountID: account.ID, ToAccountID: counterparty1.ID, Amount: 10},
+		{FromAccountID: counterparty1.ID, ToAccountID: account.ID, Amount: 20},
+		{FromAccountID: account.ID, ToAccountID: counterparty2.ID, Amount: 30},
+		{FromAccountID: counterparty2.ID, ToAccountID: account.ID, Amount: 40},
+	}
+	for _, arg := range transfers {
+		_, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name    string
+		arg     ListTransfersParams
+		amounts []int64
+	}{
+		{
+			name:    "All",
+			arg:     ListTransfersParams{AccountID: account.ID},
+			amounts: []int64{10, 20, 30, 40},
+		},
+		{
+			name:    "Outgoing",
+			arg:     ListTransfersParams{AccountID: account.ID, Direction: pgtype.Text{String: "outgoing", Valid: true}},
+			amounts: []int64{10, 30},
+		},
+		{
+			name:    "Incoming",
+			arg:     ListTransfersParams{AccountID: account.ID, Direction: pgtype.Text{String: "incoming", Valid: true}},
+			amounts: []int64{20, 40},
+		},
+		{
+			name:    "Counterparty",
+			arg:     ListTransfersParams{AccountID: account.ID, CounterpartyID: pgtype.Int8{Int64: counterparty2.ID, Valid: true}},
+			amounts: []int64{30, 40},
+		},
+		{
+			name: "AmountRange",
+			arg: ListTransfersParams{
+				AccountID: account.ID,
+				MinAmount: pgtype.Int8{Int64: 15, Valid: true},
+				MaxAmount: pgtype.Int8{Int64: 35, Valid: true},
+			},
+			amounts: []int64{20, 30},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			tc.arg.Limit = 10
+
+			result, err := testQueries.ListTransfers(context.Background(), tc.arg)
+			require.NoError(t, err)
+			require.Len(t, result, len(tc.amounts))
+
+			for j, transfer := range result {
+				require.Equal(t, tc.amounts[j], transfer.Amount)
+				require.True(t, transfer.FromAccountID == account.ID || transfer.ToAccountID == account.ID)
+			}
+		})
+	}
+}
+
+func TestListTransfersCrossCurrency(t *testing.T) {
+	store := NewStore(testConnPool)
+
+	account := createRandomAccountWithBalance(t, 1000)
+	counterparty := createRandomAccountWithBalance(t, 1000)
+
+	// Amount is in the currency of the sender, ToAmount in the currency of the recipient
+	transfers := []TransferTxParams{
+		{FromAccountID: counterparty.ID, ToAccountID: account.ID, Amount: 100, ToAmount: 10},
+		{FromAccountID: account.ID, ToAccountID: counterparty.ID, Amount: 50, ToAmount: 500},
+	}
+	for _, arg := range transfers {
+		_, err := store.TransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name      string
+		accountID int64
+		min, max  int64
+		amounts   []int64
+	}{
+		{
+			name:      "ReceivedAmount",
+			accountID: account.ID,
+			min:       5,
+			max:       20,
+			amounts:   []int64{100},
+		},
+		{
+			name:      "SentAmount",
+			accountID: account.ID,
+			min:       40,
+			max:       60,
+			amounts:   []int64{50},
+		},
+		{
+			name:      "Counterparty",
+			accountID: counterparty.ID,
+			min:       400,
+			max:       600,
+			amounts:   []int64{50},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := testQueries.ListTransfers(context.Background(), ListTransfersParams{
+				AccountID: tc.accountID,
+				MinAmount: pgtype.Int8{Int64: tc.min, Valid: true},
+				MaxAmount: pgtype.Int8{Int64: tc.max, Valid: true},
+				Limit:     10,
+			})
+			require.NoError(t, err)
+			require.Len(t, result, len(tc.amounts))
+
+			for j, transfer := range result {
+				require.Equal(t, tc.amounts[j], transfer.Amount)
+			}
+		})
+	}
+}
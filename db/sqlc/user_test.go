@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomUser(t *testing.T) User {
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	arg := CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: hashedPassword,
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+	}
+
+	user, err := testQueries.CreateUser(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, user)
+
+	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, arg.HashedPassword, user.HashedPassword)
+	require.Equal(t, arg.FullName, user.FullName)
+	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, util.DepositorRole, user.Role)
+
+	require.True(t, user.PasswordChangedAt.IsZero())
+	require.NotZero(t, user.CreatedAt)
+
+	return user
+}
+
+func TestCreateUser(t *testing.T) {
+	createRandomUser(t)
+}
+
+func TestCreateUserDuplicate(t *testing.T) {
+	store := NewStore(testConnPool)
+	user := createRandomUser(t)
+
+	_, err := store.CreateUser(context.Background(), CreateUserParams{
+		Username:       user.Username,
+		HashedPassword: user.HashedPassword,
+		FullName:       user.FullName,
+		Email:          util.RandomEmail(),
+	})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+}
+
+func TestGetUser(t *testing.T) {
+	newUser := createRandomUser(t)
+
+	retrievedUser, err := testQueries.GetUser(context.Background(), newUser.Username)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, retrievedUser)
+
+	require.Equal(t, newUser.Username, retrievedUser.Username)
+	require.Equal(t, newUser.HashedPassword, retrievedUser.HashedPassword)
+	require.Equal(t, newUser.FullName, retrievedUser.FullName)
+	require.Equal(t, newUser.Email, retrievedUser.Email)
+
+	require.WithinDuration(t, newUser.PasswordChangedAt, retrievedUser.PasswordChangedAt, time.Second)
+	require.WithinDuration(t, newUser.CreatedAt, retrievedUser.CreatedAt, time.Second)
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	newUser := createRandomUser(t)
+
+	updatedUser, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: newUser.Username,
+		Role:     util.BankerRole,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, newUser.Username, updatedUser.Username)
+	require.Equal(t, util.BankerRole, updatedUser.Role)
+}
+
+func TestUpdateUserRoleInvalid(t *testing.T) {
+	newUser := createRandomUser(t)
+
+	_, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: newUser.Username,
+		Role:     "superuser",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/numeric"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RateQuerier is the part of db.Querier the DBProvider needs
+type RateQuerier interface {
+	GetFXRate(ctx context.Context, arg db.GetFXRateParams) (db.FxRate, error)
+}
+
+// DBProvider serves rates from the fx_rates table
+type DBProvider struct {
+	querier RateQuerier
+}
+
+// NewDBProvider creates a provider reading rates with the given querier
+func NewDBProvider(querier RateQuerier) *DBProvider {
+	return &DBProvider{querier: querier}
+}
+
+// Rate returns the latest rate from base to quote that took effect at or before at
+func (provider *DBProvider) Rate(ctx context.Context, base, quote string, at time.Time) (Rate, error) {
+	return lookupEitherWay(ctx, base, quote, at, provider.lookup)
+}
+
+func (provider *DBProvider) lookup(ctx context.Context, base, quote string, at time.Time) (Rate, error) {
+	row, err := provider.querier.GetFXRate(ctx, db.GetFXRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		At:            at,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return Rate{}, ErrRateNotFound
+		}
+		return Rate{}, err
+	}
+
+	value, err := numeric.ToRat(row.Rate)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return Rate{
+		EffectiveAt: row.EffectiveAt,
+		Value:       value,
+		Base:        row.BaseCurrency,
+		Quote:       row.QuoteCurrency,
+	}, nil
+}
+
+// RatToNumeric converts x, rounded to RateScale decimals, to a numeric column value
+func RatToNumeric(x *big.Rat) (pgtype.Numeric, error) {
+	return numeric.FromRat(roundToScale(x, RateScale), RateScale)
+}
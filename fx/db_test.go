@@ -0,0 +1,50 @@
+package fx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRateQuerier serves fx_rates rows from memory
+type stubRateQuerier map[string]db.FxRate
+
+func (q stubRateQuerier) GetFXRate(_ context.Context, arg db.GetFXRateParams) (db.FxRate, error) {
+	row, ok := q[pairKey(arg.BaseCurrency, arg.QuoteCurrency)]
+	if !ok || row.EffectiveAt.After(arg.At) {
+		return db.FxRate{}, db.ErrRecordNotFound
+	}
+	return row, nil
+}
+
+func TestDBProviderRate(t *testing.T) {
+	effectiveAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rate, err := RatToNumeric(big.NewRat(92, 100))
+	require.NoError(t, err)
+
+	provider := NewDBProvider(stubRateQuerier{
+		"USD/EUR": {
+			BaseCurrency:  "USD",
+			QuoteCurrency: "EUR",
+			Rate:          rate,
+			EffectiveAt:   effectiveAt,
+		},
+	})
+
+	got, err := provider.Rate(context.Background(), "USD", "EUR", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Zero(t, big.NewRat(92, 100).Cmp(got.Value))
+	require.Equal(t, effectiveAt, got.EffectiveAt)
+
+	got, err = provider.Rate(context.Background(), "EUR", "USD", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Zero(t, big.NewRat(100, 92).Cmp(got.Value))
+
+	_, err = provider.Rate(context.Background(), "USD", "EUR", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
@@ -0,0 +1,95 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aronreisx/bubblebank/currency"
+)
+
+// fileRate is a rate as written in a rate file
+type fileRate struct {
+	EffectiveAt time.Time `json:"effective_at"`
+	Base        string    `json:"base"`
+	Quote       string    `json:"quote"`
+	Rate        string    `json:"rate"`
+}
+
+// FileProvider serves rates loaded from a JSON file, such as:
+//
+//	[{"base": "USD", "quote": "EUR", "rate": "0.92", "effective_at": "2026-01-01T00:00:00Z"}]
+type FileProvider struct {
+	// rates holds the rates of each pair, sorted by effective time
+	rates map[string][]Rate
+}
+
+// NewFileProvider loads the rates of a JSON rate file
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- the path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rate file: %w", err)
+	}
+
+	var entries []fileRate
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("cannot parse rate file %s: %w", path, err)
+	}
+
+	provider := &FileProvider{rates: make(map[string][]Rate)}
+	for i, entry := range entries {
+		if !currency.IsSupported(entry.Base) || !currency.IsSupported(entry.Quote) || entry.Base == entry.Quote {
+			return nil, fmt.Errorf("rate file %s entry %d: invalid pair %s/%s", path, i, entry.Base, entry.Quote)
+		}
+
+		value, err := ParseRate(entry.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("rate file %s entry %d: %w", path, i, err)
+		}
+
+		key := pairKey(entry.Base, entry.Quote)
+		provider.rates[key] = append(provider.rates[key], Rate{
+			EffectiveAt: entry.EffectiveAt,
+			Value:       value,
+			Base:        entry.Base,
+			Quote:       entry.Quote,
+		})
+	}
+
+	for _, rates := range provider.rates {
+		slices.SortFunc(rates, func(a, b Rate) int {
+			return a.EffectiveAt.Compare(b.EffectiveAt)
+		})
+	}
+
+	return provider, nil
+}
+
+// Rate returns the latest rate from base to quote that took effect at or before at
+func (provider *FileProvider) Rate(ctx context.Context, base, quote string, at time.Time) (Rate, error) {
+	return lookupEitherWay(ctx, base, quote, at, provider.lookup)
+}
+
+func (provider *FileProvider) lookup(_ context.Context, base, quote string, at time.Time) (Rate, error) {
+	rates := provider.rates[pairKey(base, quote)]
+
+	// Index of the first rate taking effect after at
+	i, _ := slices.BinarySearchFunc(rates, at, func(rate Rate, at time.Time) int {
+		if rate.EffectiveAt.After(at) {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 {
+		return Rate{}, ErrRateNotFound
+	}
+
+	return rates[i-1], nil
+}
+
+func pairKey(base, quote string) string {
+	return base + "/" + quote
+}
@@ -0,0 +1,113 @@
+package fx
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileProvider(t *testing.T) *FileProvider {
+	provider, err := NewFileProvider(filepath.Join("testdata", "rates.json"))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestFileProviderRate(t *testing.T) {
+	provider := newTestFileProvider(t)
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name        string
+		base        string
+		quote       string
+		at          time.Time
+		rate        *big.Rat
+		effectiveAt time.Time
+	}{
+		{
+			name:        "FirstRate",
+			base:        "USD",
+			quote:       "EUR",
+			at:          time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			rate:        big.NewRat(9, 10),
+			effectiveAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "LatestRate",
+			base:        "USD",
+			quote:       "EUR",
+			at:          time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+			rate:        big.NewRat(92, 100),
+			effectiveAt: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "EffectiveAtBoundary",
+			base:        "USD",
+			quote:       "EUR",
+			at:          time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			rate:        big.NewRat(92, 100),
+			effectiveAt: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "InversePair",
+			base:        "EUR",
+			quote:       "USD",
+			at:          time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+			rate:        big.NewRat(100, 92),
+			effectiveAt: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := provider.Rate(context.Background(), tc.base, tc.quote, tc.at)
+			require.NoError(t, err)
+			require.Equal(t, tc.base, rate.Base)
+			require.Equal(t, tc.quote, rate.Quote)
+			require.Zero(t, tc.rate.Cmp(rate.Value), rate.Value.String())
+			require.Equal(t, tc.effectiveAt, rate.EffectiveAt)
+		})
+	}
+}
+
+func TestFileProviderRateNotFound(t *testing.T) {
+	provider := newTestFileProvider(t)
+
+	// Before the first rate took effect
+	_, err := provider.Rate(context.Background(), "USD", "EUR", time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	// Unknown pair
+	_, err = provider.Rate(context.Background(), "CAD", "EUR", time.Now())
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestNewFileProviderInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"UnsupportedCurrency": `[{"base": "USD", "quote": "XXX", "rate": "1", "effective_at": "2026-01-01T00:00:00Z"}]`,
+		"SameCurrency":        `[{"base": "USD", "quote": "USD", "rate": "1", "effective_at": "2026-01-01T00:00:00Z"}]`,
+		"NegativeRate":        `[{"base": "USD", "quote": "EUR", "rate": "-1", "effective_at": "2026-01-01T00:00:00Z"}]`,
+		"InvalidRate":         `[{"base": "USD", "quote": "EUR", "rate": "abc", "effective_at": "2026-01-01T00:00:00Z"}]`,
+		"InvalidJSON":         `{`,
+	}
+
+	for name, content := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rates.json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			_, err := NewFileProvider(path)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := NewFileProvider(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
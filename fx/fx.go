@@ -0,0 +1,164 @@
+// Package fx converts amounts between currencies using exchange rates
+// read from a RateProvider, such as a local rate file or the fx_rates table.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aronreisx/bubblebank/currency"
+	"github.com/aronreisx/bubblebank/numeric"
+)
+
+// RateScale is the number of decimals rates are kept with, matching the fx_rates.rate column
+const RateScale = numeric.RateScale
+
+var (
+	// ErrRateNotFound is returned when no rate is in effect for a currency pair
+	ErrRateNotFound = errors.New("exchange rate not found")
+	// ErrAmountTooSmall is returned when a converted amount rounds to zero
+	ErrAmountTooSmall = errors.New("converted amount rounds to zero")
+	// ErrAmountTooLarge is returned when a converted amount does not fit in an int64
+	ErrAmountTooLarge = errors.New("converted amount is too large")
+)
+
+// Rate is the price of one unit of Base expressed in Quote, in effect from EffectiveAt
+type Rate struct {
+	EffectiveAt time.Time
+	Value       *big.Rat
+	Base        string
+	Quote       string
+}
+
+// RateProvider looks up exchange rates
+type RateProvider interface {
+	// Rate returns the latest rate from base to quote that took effect at or before at.
+	// It returns ErrRateNotFound when there is none.
+	Rate(ctx context.Context, base, quote string, at time.Time) (Rate, error)
+}
+
+// lookupFunc finds the rate of a single direction of a currency pair
+type lookupFunc func(ctx context.Context, base, quote string, at time.Time) (Rate, error)
+
+// lookupEitherWay returns the rate from base to quote, deriving it
+// from the rate from quote to base when only the inverse pair is known
+func lookupEitherWay(ctx context.Context, base, quote string, at time.Time, lookup lookupFunc) (Rate, error) {
+	rate, err := lookup(ctx, base, quote, at)
+	if !errors.Is(err, ErrRateNotFound) {
+		return rate, err
+	}
+
+	inverse, err := lookup(ctx, quote, base, at)
+	if err != nil {
+		if errors.Is(err, ErrRateNotFound) {
+			return Rate{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+		}
+		return Rate{}, err
+	}
+
+	return Rate{
+		EffectiveAt: inverse.EffectiveAt,
+		Value:       new(big.Rat).Inv(inverse.Value),
+		Base:        base,
+		Quote:       quote,
+	}, nil
+}
+
+// Conversion is an amount converted from one currency to another
+type Conversion struct {
+	// RateAt is when the applied rate took effect
+	RateAt time.Time
+	// Rate is the applied rate, rounded to RateScale decimals
+	Rate       *big.Rat
+	From       string
+	To         string
+	FromAmount int64
+	ToAmount   int64
+}
+
+// Converter converts amounts in minor units between currencies
+type Converter struct {
+	provider RateProvider
+	rounding RoundingMode
+}
+
+// NewConverter creates a converter that rounds converted amounts with the given mode
+func NewConverter(provider RateProvider, rounding RoundingMode) *Converter {
+	return &Converter{
+		provider: provider,
+		rounding: rounding,
+	}
+}
+
+// Convert converts amount, in minor units of from, into minor units of to
+// using the rate in effect at the given time
+func (c *Converter) Convert(ctx context.Context, amount int64, from, to string, at time.Time) (Conversion, error) {
+	fromCurrency, err := currency.Get(from)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	toCurrency, err := currency.Get(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	if from == to {
+		return Conversion{
+			RateAt:     at,
+			Rate:       big.NewRat(1, 1),
+			From:       from,
+			To:         to,
+			FromAmount: amount,
+			ToAmount:   amount,
+		}, nil
+	}
+
+	rate, err := c.provider.Rate(ctx, from, to, at)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	// Round the rate first, so that the recorded rate is the one the amount was converted with
+	value := roundToScale(rate.Value, RateScale)
+
+	// amount is in units of 10^-fromExponent and the result in units of 10^-toExponent
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), value)
+	converted.Mul(converted, numeric.Pow10(toCurrency.Exponent-fromCurrency.Exponent))
+
+	toAmount := c.rounding.round(converted)
+	if !toAmount.IsInt64() {
+		return Conversion{}, ErrAmountTooLarge
+	}
+	if amount > 0 && toAmount.Sign() <= 0 {
+		return Conversion{}, fmt.Errorf("%w: %s %s", ErrAmountTooSmall, fromCurrency.Format(amount), from)
+	}
+
+	return Conversion{
+		RateAt:     rate.EffectiveAt,
+		Rate:       value,
+		From:       from,
+		To:         to,
+		FromAmount: amount,
+		ToAmount:   toAmount.Int64(),
+	}, nil
+}
+
+// ParseRate parses a positive decimal rate such as "0.92"
+func ParseRate(s string) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(s)
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q", s)
+	}
+	return value, nil
+}
+
+// roundToScale rounds x half to even to the given number of decimals
+func roundToScale(x *big.Rat, scale int) *big.Rat {
+	factor := numeric.Pow10(scale)
+	scaled := RoundHalfEven.round(new(big.Rat).Mul(x, factor))
+	return new(big.Rat).Quo(new(big.Rat).SetInt(scaled), factor)
+}
@@ -0,0 +1,183 @@
+package fx
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	converter := NewConverter(newTestFileProvider(t), RoundHalfEven)
+	at := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		amount   int64
+		toAmount int64
+		rate     string
+	}{
+		{
+			// 10.00 USD * 0.92 = 9.20 EUR
+			name:     "SameExponent",
+			from:     "USD",
+			to:       "EUR",
+			amount:   1000,
+			toAmount: 920,
+			rate:     "0.920000000000",
+		},
+		{
+			// 10.00 USD * 150.25 = 1502.5 JPY, rounded to the even yen
+			name:     "ToZeroExponent",
+			from:     "USD",
+			to:       "JPY",
+			amount:   1000,
+			toAmount: 1502,
+			rate:     "150.250000000000",
+		},
+		{
+			// 1.000 KWD * 3.25 = 3.25 USD
+			name:     "FromHigherExponent",
+			from:     "KWD",
+			to:       "USD",
+			amount:   1000,
+			toAmount: 325,
+			rate:     "3.250000000000",
+		},
+		{
+			// 100 JPY / 150.25 = 0.665557... USD, through the inverse rate rounded to 12 decimals
+			name:     "InverseRate",
+			from:     "JPY",
+			to:       "USD",
+			amount:   100,
+			toAmount: 67,
+			rate:     "0.006655574043",
+		},
+		{
+			// The rate is rounded to 12 decimals before being applied
+			name:     "RoundedRate",
+			from:     "GBP",
+			to:       "EUR",
+			amount:   300,
+			toAmount: 400,
+			rate:     "1.333333333333",
+		},
+		{
+			name:     "SameCurrency",
+			from:     "USD",
+			to:       "USD",
+			amount:   1234,
+			toAmount: 1234,
+			rate:     "1.000000000000",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			conversion, err := converter.Convert(context.Background(), tc.amount, tc.from, tc.to, at)
+			require.NoError(t, err)
+			require.Equal(t, tc.from, conversion.From)
+			require.Equal(t, tc.to, conversion.To)
+			require.Equal(t, tc.amount, conversion.FromAmount)
+			require.Equal(t, tc.toAmount, conversion.ToAmount)
+			require.Equal(t, tc.rate, conversion.Rate.FloatString(RateScale))
+		})
+	}
+}
+
+func TestConvertRecordsRateTime(t *testing.T) {
+	converter := NewConverter(newTestFileProvider(t), RoundHalfEven)
+
+	conversion, err := converter.Convert(context.Background(), 1000, "USD", "EUR", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, int64(900), conversion.ToAmount)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), conversion.RateAt)
+}
+
+func TestConvertErrors(t *testing.T) {
+	converter := NewConverter(newTestFileProvider(t), RoundHalfEven)
+	at := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := converter.Convert(context.Background(), 1000, "CAD", "EUR", at)
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	// 1 JPY is less than a cent, which rounds down to nothing
+	_, err = NewConverter(newTestFileProvider(t), RoundDown).Convert(context.Background(), 1, "JPY", "USD", at)
+	require.ErrorIs(t, err, ErrAmountTooSmall)
+
+	_, err = converter.Convert(context.Background(), math.MaxInt64, "USD", "JPY", at)
+	require.ErrorIs(t, err, ErrAmountTooLarge)
+
+	_, err = converter.Convert(context.Background(), 1000, "XXX", "USD", at)
+	require.Error(t, err)
+}
+
+func TestConvertRoundingModes(t *testing.T) {
+	at := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// 10.00 USD is 1502.5 JPY and 10.01 USD is 1504.0025 JPY
+	testCases := map[RoundingMode][2]int64{
+		RoundHalfEven: {1502, 1504},
+		RoundHalfUp:   {1503, 1504},
+		RoundDown:     {1502, 1504},
+		RoundUp:       {1503, 1505},
+	}
+
+	for mode, expected := range testCases {
+		t.Run(string(mode), func(t *testing.T) {
+			converter := NewConverter(newTestFileProvider(t), mode)
+
+			for i, amount := range []int64{1000, 1001} {
+				conversion, err := converter.Convert(context.Background(), amount, "USD", "JPY", at)
+				require.NoError(t, err)
+				require.Equal(t, expected[i], conversion.ToAmount)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	//nolint:govet // Ignoring struct field alignment optimization in test code
+	testCases := []struct {
+		x        *big.Rat
+		halfEven int64
+		halfUp   int64
+		down     int64
+		up       int64
+	}{
+		{big.NewRat(5, 2), 2, 3, 2, 3},
+		{big.NewRat(7, 2), 4, 4, 3, 4},
+		{big.NewRat(-5, 2), -2, -3, -2, -3},
+		{big.NewRat(21, 10), 2, 2, 2, 3},
+		{big.NewRat(29, 10), 3, 3, 2, 3},
+		{big.NewRat(3, 1), 3, 3, 3, 3},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.halfEven, RoundHalfEven.round(tc.x).Int64(), tc.x.String())
+		require.Equal(t, tc.halfUp, RoundHalfUp.round(tc.x).Int64(), tc.x.String())
+		require.Equal(t, tc.down, RoundDown.round(tc.x).Int64(), tc.x.String())
+		require.Equal(t, tc.up, RoundUp.round(tc.x).Int64(), tc.x.String())
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("")
+	require.NoError(t, err)
+	require.Equal(t, RoundHalfEven, mode)
+
+	mode, err = ParseRoundingMode("half_up")
+	require.NoError(t, err)
+	require.Equal(t, RoundHalfUp, mode)
+
+	_, err = ParseRoundingMode("ceiling")
+	require.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package fx
+
+import "fmt"
+
+// Supported rate sources
+const (
+	SourceNone = "none"
+	SourceFile = "file"
+	SourceDB   = "db"
+)
+
+// NewProvider creates the rate provider of the given source.
+// It returns a nil provider for SourceNone, which disables cross-currency transfers.
+func NewProvider(source string, path string, querier RateQuerier) (RateProvider, error) {
+	switch source {
+	case "", SourceNone:
+		return nil, nil
+	case SourceFile:
+		return NewFileProvider(path)
+	case SourceDB:
+		return NewDBProvider(querier), nil
+	default:
+		return nil, fmt.Errorf("unsupported rate source %q", source)
+	}
+}
@@ -0,0 +1,63 @@
+package fx
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// RoundingMode decides how a converted amount is rounded to a whole minor unit
+type RoundingMode string
+
+// Supported rounding modes
+const (
+	// RoundHalfEven rounds to the nearest unit, ties to the even unit (banker's rounding)
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundHalfUp rounds to the nearest unit, ties away from zero
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundDown truncates towards zero
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero
+	RoundUp RoundingMode = "up"
+)
+
+// ParseRoundingMode returns the rounding mode with the given name, an empty name means RoundHalfEven
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch mode := RoundingMode(name); mode {
+	case "":
+		return RoundHalfEven, nil
+	case RoundHalfEven, RoundHalfUp, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported rounding mode %q", name)
+	}
+}
+
+// round rounds x to an integer
+func (mode RoundingMode) round(x *big.Rat) *big.Int {
+	// QuoRem truncates towards zero, the remainder has the sign of x
+	quo, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// Compare the discarded fraction with one half
+	twice := new(big.Int).Lsh(new(big.Int).Abs(rem), 1)
+	half := twice.Cmp(x.Denom())
+
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundHalfUp:
+		away = half >= 0
+	default:
+		away = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	}
+
+	if away {
+		quo.Add(quo, big.NewInt(int64(x.Sign())))
+	}
+	return quo
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/logging"
+)
+
+// expireHolds releases the funds of expired holds every interval until ctx is done.
+// Transactions debiting an account release its expired holds themselves, the sweep
+// keeps the available balance of the other accounts up to date.
+func expireHolds(ctx context.Context, store db.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := store.ExpireHoldsTx(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("cannot expire holds", slog.Any("error", err))
+				continue
+			}
+			if expired > 0 {
+				logging.FromContext(ctx).Info("released expired holds", slog.Int64("accounts", expired))
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package logging sets up the structured logger of the service and carries
+// request-scoped loggers through contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config selects the level and format of the logs
+type Config struct {
+	// Level is one of debug, info, warn or error, empty means info
+	Level string
+	// Format is FormatJSON or FormatText, empty means FormatJSON
+	Format string
+}
+
+// New creates a logger writing to w according to config
+func New(w io.Writer, config Config) (*slog.Logger, error) {
+	var level slog.Level
+	if config.Level != "" {
+		if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q: %w", config.Level, err)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(config.Format) {
+	case "", FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q", config.Format)
+	}
+}
+
+type contextKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Config{Level: "warn"})
+	require.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept", slog.String("request_id", "abc"))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "kept", line["msg"])
+	require.Equal(t, "WARN", line["level"])
+	require.Equal(t, "abc", line["request_id"])
+
+	buf.Reset()
+	logger, err = New(&buf, Config{Level: "DEBUG", Format: FormatText})
+	require.NoError(t, err)
+
+	logger.Debug("kept")
+	require.Contains(t, buf.String(), "level=DEBUG msg=kept")
+
+	_, err = New(&buf, Config{Level: "verbose"})
+	require.Error(t, err)
+
+	_, err = New(&buf, Config{Format: "xml"})
+	require.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	require.Equal(t, slog.Default(), FromContext(context.Background()))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := WithLogger(context.Background(), logger)
+	require.Equal(t, logger, FromContext(ctx))
+}
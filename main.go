@@ -2,25 +2,85 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	api "github.com/aronreisx/bubblebank/api"
 	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/logging"
+	"github.com/aronreisx/bubblebank/metrics"
+	"github.com/aronreisx/bubblebank/tracing"
 	"github.com/aronreisx/bubblebank/util"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 
 	_ "github.com/aronreisx/bubblebank/db/migrations"
 )
 
+// errLedgerInconsistent fails the reconcile subcommand once it has written its report
+var errLedgerInconsistent = errors.New("ledger is inconsistent, see the reconciliation report")
+
 func main() {
+	// Exit from a single place, once the deferred cleanups of run are done
+	if err := run(); err != nil {
+		slog.Error("bubblebank stopped", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run() error {
 	config, err := util.LoadConfig(".")
 	if err != nil {
-		log.Fatalf("cannot load config: %v", err)
+		return fmt.Errorf("cannot load config: %w", err)
 	}
 
-	log.Printf("Connecting to PostgreSQL with Host: '%s', Port: '%s', User: '%s', Database: '%s'",
-		config.DBHost, config.DBPort, config.DBUser, config.DBName)
+	// Log to stderr, the reconcile subcommand writes its report to stdout
+	logger, err := logging.New(os.Stderr, logging.Config{
+		Level:  config.LogLevel,
+		Format: config.LogFormat,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set up logging: %w", err)
+	}
+	slog.SetDefault(logger)
+
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		consistent, err := runReconcile(config, os.Args[2:])
+		if err != nil {
+			return fmt.Errorf("reconciliation failed: %w", err)
+		}
+		if !consistent {
+			return errLedgerInconsistent
+		}
+		return nil
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     config.TraceExporter,
+		OTLPEndpoint: config.TraceOTLPEndpoint,
+		SampleRatio:  config.TraceSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set up tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("cannot flush traces", slog.Any("error", err))
+		}
+	}()
+
+	slog.Info("Connecting to PostgreSQL",
+		slog.String("host", config.DBHost),
+		slog.String("port", config.DBPort),
+		slog.String("user", config.DBUser),
+		slog.String("database", config.DBName),
+	)
 
 	// Construct connection string with explicit parameters
 	connString := util.ConstructDBConnectionString(
@@ -32,36 +92,103 @@ func main() {
 	)
 
 	// Run database migrations using in-code Go migrations
-	log.Printf("Running database migrations")
+	slog.Info("Running database migrations")
 	if err := util.RunDBMigration(config.MigrationsFolder, connString); err != nil {
-		log.Fatalf("migration failed: %v", err)
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	conn, err := newConnPool(connString)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Stop on SIGINT or SIGTERM, such as during deploys
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serviceMetrics := metrics.New()
+	serviceMetrics.RegisterPool(conn)
+
+	store := db.NewStore(conn,
+		db.WithRetryHook(serviceMetrics.ObserveTxRetry),
+		db.WithRollbackHook(serviceMetrics.ObserveTxRollback),
+	)
+	if config.HoldExpiryInterval > 0 {
+		sweepCtx := logging.WithLogger(ctx, logger.With(slog.String("job", "expire_holds")))
+		go expireHolds(sweepCtx, store, config.HoldExpiryInterval)
 	}
 
+	// database/sql view of the pool for goose, closing it leaves the pool open
+	migrationDB := stdlib.OpenDBFromPool(conn)
+	defer migrationDB.Close()
+
+	server, err := api.NewServer(config, store,
+		api.WithMetrics(serviceMetrics),
+		api.WithLogger(logger),
+		api.WithReadinessCheck("migrations", api.MigrationCheck(func(ctx context.Context) (int64, int64, error) {
+			return util.MigrationVersions(ctx, migrationDB, config.MigrationsFolder)
+		})),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create server: %w", err)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start(":" + config.ServerPort)
+	}()
+
+	server.SetReady()
+	slog.Info("Server is ready to receive traffic", slog.String("port", config.ServerPort))
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("cannot start server: %w", err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process right away
+	stop()
+
+	// Keep serving while load balancers notice the failing readiness probe
+	// and stop sending new requests, before refusing connections
+	slog.Info("Shutting down, waiting for load balancers to stop sending traffic",
+		slog.Duration("drain_delay", config.ShutdownDrainDelay))
+	server.SetNotReady()
+	time.Sleep(config.ShutdownDrainDelay)
+
+	slog.Info("Draining in-flight requests",
+		slog.Duration("grace_period", config.ShutdownGracePeriod))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownGracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("cannot drain in-flight requests", slog.Any("error", err))
+	}
+
+	slog.Info("Server stopped, closing the database connections")
+	return nil
+}
+
+// newConnPool connects to PostgreSQL
+func newConnPool(connString string) (*pgxpool.Pool, error) {
 	// Parse the connection string into a pgxpool config
 	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		log.Fatalf("unable to parse config: %v\n", err)
+		return nil, fmt.Errorf("unable to parse config: %w", err)
 	}
 
 	// Force TCP connection by setting the dial function
 	poolConfig.ConnConfig.Config.DialFunc = (&net.Dialer{}).DialContext
 
+	// Trace every query, as children of the span of the request or transaction issuing it
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
 	// Create new connection pool with our custom configuration
 	conn, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 
 	if err != nil {
-		log.Printf("Database connection error: %v", err)
-		log.Fatalf("Database service unavailable")
+		return nil, fmt.Errorf("database service unavailable: %w", err)
 	}
 
-	store := db.NewStore(conn)
-	server := api.NewServer(store)
-
-	server.SetReady()
-	log.Println("Server is ready to receive traffic")
-
-	err = server.Start(":" + config.ServerPort)
-	if err != nil {
-		log.Fatalf("cannot start server: %v", err)
-	}
+	return conn, nil
 }
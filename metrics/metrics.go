@@ -0,0 +1,138 @@
+// Package metrics exposes the Prometheus metrics of the service: HTTP traffic,
+// database pool and transaction activity, and ledger activity.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bubblebank"
+
+// Metrics holds the collectors of the service in a registry of its own,
+// so that several servers, such as in tests, do not clash.
+type Metrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	txRetries        *prometheus.CounterVec
+	txRollbacks      prometheus.Counter
+	transfers        *prometheus.CounterVec
+	transferVolume   *prometheus.CounterVec
+	cashTransactions *prometheus.CounterVec
+}
+
+// New creates the collectors of the service along with the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests handled, by route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests, by route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		txRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "tx_retries_total",
+			Help:      "Number of database transactions retried, by the error code that caused the retry.",
+		}, []string{"code"}),
+		txRollbacks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "tx_rollbacks_total",
+			Help:      "Number of database transactions rolled back.",
+		}),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfers_total",
+			Help:      "Number of transfers created, by currency of the debited account and kind.",
+		}, []string{"currency", "kind"}),
+		transferVolume: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "transfer_volume_total",
+			Help:      "Amount moved by transfers in minor units, by currency of the debited account.",
+		}, []string{"currency"}),
+		cashTransactions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ledger",
+			Name:      "cash_transactions_total",
+			Help:      "Number of deposits and withdrawals, by currency and kind.",
+		}, []string{"currency", "kind"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.txRetries,
+		m.txRollbacks,
+		m.transfers,
+		m.transferVolume,
+		m.cashTransactions,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterPool exposes the statistics of a connection pool
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(pool.Stat))
+}
+
+// ObserveRequest records a handled HTTP request
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.requestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveTxRetry records a retried transaction, it is meant to be a db.RetryHook
+func (m *Metrics) ObserveTxRetry(_ context.Context, _ int, err error) {
+	code := db.ErrorCode(err)
+	if code == "" {
+		code = "unknown"
+	}
+	m.txRetries.WithLabelValues(code).Inc()
+}
+
+// ObserveTxRollback records a rolled back transaction, it is meant to be a db.RollbackHook
+func (m *Metrics) ObserveTxRollback(_ context.Context, _ error) {
+	m.txRollbacks.Inc()
+}
+
+// ObserveTransfer records a transfer of amount minor units of currency.
+// kind is either "transfer" or "reversal", telling regular transfers and reversals apart.
+func (m *Metrics) ObserveTransfer(currency, kind string, amount int64) {
+	m.transfers.WithLabelValues(currency, kind).Inc()
+	m.transferVolume.WithLabelValues(currency).Add(float64(amount))
+}
+
+// ObserveCash records a deposit or a withdrawal
+func (m *Metrics) ObserveCash(currency, kind string) {
+	m.cashTransactions.WithLabelValues(currency, kind).Inc()
+}
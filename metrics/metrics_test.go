@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveRequest(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest(http.MethodPost, "/transfers", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/transfers", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/transfers", http.StatusUnprocessableEntity, time.Millisecond)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodPost, "/transfers", "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodPost, "/transfers", "422")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.requestDuration))
+}
+
+func TestObserveTx(t *testing.T) {
+	m := New()
+
+	m.ObserveTxRetry(context.Background(), 1, &pgconn.PgError{Code: "40001"})
+	m.ObserveTxRetry(context.Background(), 1, errors.New("not a PostgreSQL error"))
+	m.ObserveTxRollback(context.Background(), errors.New("fn failed"))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.txRetries.WithLabelValues("40001")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.txRetries.WithLabelValues("unknown")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.txRollbacks))
+}
+
+func TestObserveLedger(t *testing.T) {
+	m := New()
+
+	m.ObserveTransfer("USD", "transfer", 150)
+	m.ObserveTransfer("USD", "reversal", 50)
+	m.ObserveCash("EUR", "deposit")
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.transfers.WithLabelValues("USD", "transfer")))
+	require.Equal(t, 200.0, testutil.ToFloat64(m.transferVolume.WithLabelValues("USD")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.cashTransactions.WithLabelValues("EUR", "deposit")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ObserveTransfer("USD", "transfer", 150)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	m.Handler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `bubblebank_ledger_transfer_volume_total{currency="USD"} 150`)
+	require.Contains(t, recorder.Body.String(), "go_goroutines")
+}
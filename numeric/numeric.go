@@ -0,0 +1,46 @@
+// Package numeric converts between PostgreSQL numeric column values and rational numbers,
+// so that exchange rates are computed without floating point errors.
+package numeric
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RateScale is the number of decimals of the rate columns of the fx_rates and transfers tables
+const RateScale = 12
+
+// ToRat converts a finite numeric column value to a rational number
+func ToRat(n pgtype.Numeric) (*big.Rat, error) {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite || n.Int == nil {
+		return nil, errors.New("invalid numeric value")
+	}
+
+	// n is Int * 10^Exp
+	value := new(big.Rat).SetInt(n.Int)
+	return value.Mul(value, Pow10(int(n.Exp))), nil
+}
+
+// FromRat converts x to a numeric column value with the given number of decimals,
+// rounding half away from zero
+func FromRat(x *big.Rat, scale int) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	err := n.Scan(x.FloatString(scale))
+	return n, err
+}
+
+// Pow10 returns 10^n, n may be negative
+func Pow10(n int) *big.Rat {
+	exp := int64(n)
+	if exp < 0 {
+		exp = -exp
+	}
+
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
@@ -0,0 +1,49 @@
+package numeric
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{"0.92", "150.25", "0.006655574043", "1", "1200"} {
+		value, ok := new(big.Rat).SetString(s)
+		require.True(t, ok)
+
+		n, err := FromRat(value, RateScale)
+		require.NoError(t, err)
+
+		back, err := ToRat(n)
+		require.NoError(t, err)
+		require.Zero(t, value.Cmp(back), s)
+	}
+}
+
+func TestFromRatRounds(t *testing.T) {
+	n, err := FromRat(big.NewRat(2, 3), 4)
+	require.NoError(t, err)
+
+	value, err := ToRat(n)
+	require.NoError(t, err)
+	require.Zero(t, big.NewRat(6667, 10000).Cmp(value))
+}
+
+func TestToRatInvalid(t *testing.T) {
+	for _, n := range []pgtype.Numeric{
+		{},
+		{Valid: true, NaN: true},
+		{Valid: true, InfinityModifier: pgtype.Infinity},
+	} {
+		_, err := ToRat(n)
+		require.Error(t, err)
+	}
+}
+
+func TestPow10(t *testing.T) {
+	require.Zero(t, big.NewRat(1, 1).Cmp(Pow10(0)))
+	require.Zero(t, big.NewRat(1000, 1).Cmp(Pow10(3)))
+	require.Zero(t, big.NewRat(1, 100).Cmp(Pow10(-2)))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+
+	db "github.com/aronreisx/bubblebank/db/sqlc"
+	"github.com/aronreisx/bubblebank/util"
+)
+
+// runReconcile implements the reconcile subcommand. It writes a JSON report of the
+// ledger's integrity and reports whether the ledger is consistent.
+//
+//	bubblebank reconcile [-batch-size n] [-repair] [-output report.json]
+func runReconcile(config util.Config, args []string) (bool, error) {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", db.DefaultReconcileBatchSize, "number of rows read per query")
+	repair := flags.Bool("repair", false, "set drifting balances to the sum of their entries")
+	output := flags.String("output", "", "write the report to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	if *batchSize <= 0 || *batchSize > math.MaxInt32 {
+		return false, errors.New("batch size must be a positive 32-bit integer")
+	}
+
+	connString := util.ConstructDBConnectionString(
+		config.DBUser,
+		config.DBPass,
+		config.DBHost,
+		config.DBPort,
+		config.DBName,
+	)
+	conn, err := newConnPool(connString)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	store := db.NewStore(conn)
+	report, err := store.Reconcile(context.Background(), db.ReconcileParams{
+		BatchSize: int32(*batchSize), // #nosec G115 -- checked above
+		Repair:    *repair,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return false, fmt.Errorf("cannot create report file: %w", err)
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				slog.Error("cannot close report file", slog.Any("error", err))
+			}
+		}()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return false, fmt.Errorf("cannot write report: %w", err)
+	}
+
+	slog.Info("Reconciliation completed",
+		slog.Int64("accounts_scanned", report.AccountsScanned),
+		slog.Int64("transfers_scanned", report.TransfersScanned),
+		slog.Int("balance_drifts", len(report.BalanceDrifts)),
+		slog.Int("orphaned_entries", len(report.OrphanedEntries)),
+		slog.Int("transfer_mismatches", len(report.TransferMismatches)),
+	)
+
+	return report.Consistent(), nil
+}
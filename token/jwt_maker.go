@@ -0,0 +1,122 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const minSecretKeySize = 32
+
+// jwtClaims are the registered JWT claims plus the user's role and the purpose of the token
+type jwtClaims struct {
+	Role    string `json:"role"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// JWTMaker is a JSON Web Token maker
+type JWTMaker struct {
+	signingMethod jwt.SigningMethod
+	signingKey    interface{}
+	verifyingKey  interface{}
+}
+
+// NewJWTMaker creates a new JWTMaker signing tokens with HS256
+func NewJWTMaker(secretKey string) (Maker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+
+	return &JWTMaker{
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    []byte(secretKey),
+		verifyingKey:  []byte(secretKey),
+	}, nil
+}
+
+// NewEdDSAJWTMaker creates a new JWTMaker signing tokens with EdDSA.
+// The key is used as the seed of the Ed25519 private key.
+func NewEdDSAJWTMaker(seed string) (Maker, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", ed25519.SeedSize)
+	}
+
+	privateKey := ed25519.NewKeyFromSeed([]byte(seed))
+
+	return &JWTMaker{
+		signingMethod: jwt.SigningMethodEdDSA,
+		signingKey:    privateKey,
+		verifyingKey:  privateKey.Public(),
+	}, nil
+}
+
+// CreateToken creates a new token for a specific username, role, purpose and duration
+func (maker *JWTMaker) CreateToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, purpose, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims := jwtClaims{
+		Role:    payload.Role,
+		Purpose: payload.Purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+	}
+
+	jwtToken := jwt.NewWithClaims(maker.signingMethod, claims)
+	token, err := jwtToken.SignedString(maker.signingKey)
+	return token, payload, err
+}
+
+// VerifyToken checks if the token is valid and was issued for purpose
+func (maker *JWTMaker) VerifyToken(token string, purpose string) (*Payload, error) {
+	keyFunc := func(_ *jwt.Token) (interface{}, error) {
+		return maker.verifyingKey, nil
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, keyFunc,
+		jwt.WithValidMethods([]string{maker.signingMethod.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := jwtToken.Claims.(*jwtClaims)
+	if !ok || claims.IssuedAt == nil {
+		return nil, ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		Username:  claims.Subject,
+		Role:      claims.Role,
+		Purpose:   claims.Purpose,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiredAt: claims.ExpiresAt.Time,
+	}
+	if err := payload.checkPurpose(purpose); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,140 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTMaker(t *testing.T) {
+	hs256Maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	eddsaMaker, err := NewEdDSAJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	makers := map[string]Maker{
+		"HS256": hs256Maker,
+		"EdDSA": eddsaMaker,
+	}
+
+	for name, maker := range makers {
+		t.Run(name, func(t *testing.T) {
+			username := util.RandomOwner()
+			role := util.BankerRole
+			duration := time.Minute
+
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			token, payload, err := maker.CreateToken(username, role, PurposeAccess, duration)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+			require.NotEmpty(t, payload)
+
+			payload, err = maker.VerifyToken(token, PurposeAccess)
+			require.NoError(t, err)
+			require.NotEmpty(t, payload)
+
+			require.NotZero(t, payload.ID)
+			require.Equal(t, username, payload.Username)
+			require.Equal(t, role, payload.Role)
+			require.Equal(t, PurposeAccess, payload.Purpose)
+			require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+			require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+		})
+	}
+}
+
+func TestExpiredJWTToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeAccess, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token, PurposeAccess)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTTokenAlgNone(t *testing.T) {
+	payload, err := NewPayload(util.RandomOwner(), util.AdminRole, PurposeAccess, time.Minute)
+	require.NoError(t, err)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		ID:        payload.ID.String(),
+		Subject:   payload.Username,
+		IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+		ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+	})
+	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token, PurposeAccess)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTTokenWrongAlgorithm(t *testing.T) {
+	hs256Maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	eddsaMaker, err := NewEdDSAJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	// An EdDSA verifier must not accept an HS256 token
+	token, _, err := hs256Maker.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeAccess, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := eddsaMaker.VerifyToken(token, PurposeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestJWTTokenWrongPurpose(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeRefresh, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token, PurposeAccess)
+	require.ErrorIs(t, err, ErrWrongPurpose)
+	require.Nil(t, payload)
+
+	payload, err = maker.VerifyToken(token, PurposeRefresh)
+	require.NoError(t, err)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+}
+
+func TestJWTMakerInvalidKeySize(t *testing.T) {
+	_, err := NewJWTMaker(util.RandomString(31))
+	require.Error(t, err)
+
+	_, err = NewEdDSAJWTMaker(util.RandomString(31))
+	require.Error(t, err)
+}
+
+func TestNewMaker(t *testing.T) {
+	key := util.RandomString(32)
+
+	for _, tokenType := range []string{TypePaseto, TypeJWTHS256, TypeJWTEdDSA} {
+		maker, err := NewMaker(tokenType, key)
+		require.NoError(t, err)
+		require.NotNil(t, maker)
+	}
+
+	_, err := NewMaker("unknown", key)
+	require.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package token
+
+import (
+	"fmt"
+	"time"
+)
+
+// Supported token types
+const (
+	TypePaseto   = "paseto"
+	TypeJWTHS256 = "jwt-hs256"
+	TypeJWTEdDSA = "jwt-eddsa"
+)
+
+// Maker is an interface for managing tokens
+type Maker interface {
+	// CreateToken creates a new token for a specific username, role, purpose and duration
+	CreateToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error)
+
+	// VerifyToken checks if the token is valid and was issued for purpose
+	VerifyToken(token string, purpose string) (*Payload, error)
+}
+
+// NewMaker creates a token maker of the given type, signing tokens with key
+func NewMaker(tokenType string, key string) (Maker, error) {
+	switch tokenType {
+	case TypePaseto:
+		return NewPasetoMaker(key)
+	case TypeJWTHS256:
+		return NewJWTMaker(key)
+	case TypeJWTEdDSA:
+		return NewEdDSAJWTMaker(key)
+	default:
+		return nil, fmt.Errorf("unsupported token type %q", tokenType)
+	}
+}
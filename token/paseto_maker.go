@@ -0,0 +1,120 @@
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+)
+
+// Custom claims carrying the user's role and the purpose of the token
+const (
+	roleClaim    = "role"
+	purposeClaim = "purpose"
+)
+
+// PasetoMaker is a PASETO v4 local token maker
+type PasetoMaker struct {
+	symmetricKey paseto.V4SymmetricKey
+}
+
+// NewPasetoMaker creates a new PasetoMaker
+func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	key, err := paseto.V4SymmetricKeyFromBytes([]byte(symmetricKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key size: must be exactly 32 characters: %w", err)
+	}
+
+	return &PasetoMaker{symmetricKey: key}, nil
+}
+
+// CreateToken creates a new token for a specific username, role, purpose and duration
+func (maker *PasetoMaker) CreateToken(username string, role string, purpose string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, purpose, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	token := paseto.NewToken()
+	token.SetJti(payload.ID.String())
+	token.SetSubject(payload.Username)
+	token.SetString(roleClaim, payload.Role)
+	token.SetString(purposeClaim, payload.Purpose)
+	token.SetIssuedAt(payload.IssuedAt)
+	token.SetExpiration(payload.ExpiredAt)
+
+	return token.V4Encrypt(maker.symmetricKey, nil), payload, nil
+}
+
+// VerifyToken checks if the token is valid and was issued for purpose
+func (maker *PasetoMaker) VerifyToken(token string, purpose string) (*Payload, error) {
+	// Expiry is checked by Payload.Valid, so it can be reported as ErrExpiredToken
+	parser := paseto.NewParserWithoutExpiryCheck()
+
+	parsedToken, err := parser.ParseV4Local(maker.symmetricKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := payloadFromPaseto(parsedToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := payload.checkPurpose(purpose); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func payloadFromPaseto(token *paseto.Token) (*Payload, error) {
+	jti, err := token.GetJti()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := uuid.Parse(jti)
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := token.GetSubject()
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := token.GetString(roleClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	purpose, err := token.GetString(purposeClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	issuedAt, err := token.GetIssuedAt()
+	if err != nil {
+		return nil, err
+	}
+
+	expiredAt, err := token.GetExpiration()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payload{
+		ID:        tokenID,
+		Username:  username,
+		Role:      role,
+		Purpose:   purpose,
+		IssuedAt:  issuedAt,
+		ExpiredAt: expiredAt,
+	}, nil
+}
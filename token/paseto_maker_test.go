@@ -0,0 +1,93 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aronreisx/bubblebank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasetoMaker(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	role := util.BankerRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, PurposeAccess, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token, PurposeAccess)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, PurposeAccess, payload.Purpose)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeAccess, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token, PurposeAccess)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoToken(t *testing.T) {
+	maker1, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	maker2, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker1.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeAccess, time.Minute)
+	require.NoError(t, err)
+
+	// A token encrypted with another key must be rejected
+	payload, err := maker2.VerifyToken(token, PurposeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = maker1.VerifyToken("v4.local.invalid", PurposeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoTokenWrongPurpose(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, PurposeRefresh, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token, PurposeAccess)
+	require.ErrorIs(t, err, ErrWrongPurpose)
+	require.Nil(t, payload)
+
+	payload, err = maker.VerifyToken(token, PurposeRefresh)
+	require.NoError(t, err)
+	require.Equal(t, PurposeRefresh, payload.Purpose)
+}
+
+func TestPasetoMakerInvalidKeySize(t *testing.T) {
+	_, err := NewPasetoMaker(util.RandomString(31))
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Different types of error returned by the VerifyToken function
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+	ErrWrongPurpose = errors.New("token was not issued for this purpose")
+)
+
+// Purposes of a token. Access tokens authorize requests, refresh tokens
+// only renew access tokens as long as their session is valid.
+const (
+	PurposeAccess  = "access"
+	PurposeRefresh = "refresh"
+)
+
+// Payload contains the payload data of the token
+type Payload struct {
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Purpose   string    `json:"purpose"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// NewPayload creates a new token payload with a specific username, role, purpose and duration
+func NewPayload(username string, role string, purpose string, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payload := &Payload{
+		ID:        tokenID,
+		Username:  username,
+		Role:      role,
+		Purpose:   purpose,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+	}
+	return payload, nil
+}
+
+// Valid checks if the token payload is valid or not
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
+
+// checkPurpose checks that the token was issued for purpose
+func (payload *Payload) checkPurpose(purpose string) error {
+	if payload.Purpose != purpose {
+		return ErrWrongPurpose
+	}
+	return nil
+}
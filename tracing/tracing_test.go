@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider recording the spans ended during the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestQueryName(t *testing.T) {
+	testCases := []struct {
+		sql  string
+		name string
+	}{
+		{sql: "-- name: GetAccount :one\nSELECT * FROM accounts WHERE id = $1", name: "GetAccount"},
+		{sql: "begin", name: "BEGIN"},
+		{sql: "  select\n1", name: "SELECT"},
+		{sql: "", name: "query"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.name, queryName(tc.sql))
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := recordSpans(t)
+	tracer := QueryTracer{}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL: "-- name: AddAccountBalance :one\nUPDATE accounts SET balance = balance + $1",
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1")})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "-- name: GetAccount :one\nSELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "commit"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection lost")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "AddAccountBalance", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	// Not finding a row does not fail the span
+	require.Equal(t, "GetAccount", spans[1].Name())
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+
+	require.Equal(t, "COMMIT", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	shutdown, err = Setup(context.Background(), Config{Exporter: ExporterStdout, SampleRatio: 1})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	require.Error(t, err)
+}
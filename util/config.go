@@ -2,8 +2,9 @@ package util
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,15 +12,37 @@ import (
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variables.
 type Config struct {
-	DBImage          string `mapstructure:"DB_IMAGE"`
-	DBVersion        string `mapstructure:"DB_VERSION"`
-	DBPort           string `mapstructure:"DB_PORT"`
-	DBUser           string `mapstructure:"DB_USER"`
-	DBPass           string `mapstructure:"DB_PASS"`
-	DBName           string `mapstructure:"DB_NAME"`
-	DBHost           string `mapstructure:"DB_HOST"`
-	ServerPort       string `mapstructure:"SERVER_PORT"`
-	MigrationsFolder string `mapstructure:"MIGRATIONS_FOLDER"`
+	DBImage              string        `mapstructure:"DB_IMAGE"`
+	DBVersion            string        `mapstructure:"DB_VERSION"`
+	DBPort               string        `mapstructure:"DB_PORT"`
+	DBUser               string        `mapstructure:"DB_USER"`
+	DBPass               string        `mapstructure:"DB_PASS"`
+	DBName               string        `mapstructure:"DB_NAME"`
+	DBHost               string        `mapstructure:"DB_HOST"`
+	ServerPort           string        `mapstructure:"SERVER_PORT"`
+	ServerReadTimeout    time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ServerWriteTimeout   time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	ServerIdleTimeout    time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ShutdownDrainDelay   time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+	ShutdownGracePeriod  time.Duration `mapstructure:"SHUTDOWN_GRACE_PERIOD"`
+	MigrationsFolder     string        `mapstructure:"MIGRATIONS_FOLDER"`
+	TokenType            string        `mapstructure:"TOKEN_TYPE"`
+	TokenSigningKey      string        `mapstructure:"TOKEN_SIGNING_KEY"`
+	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	FXRateSource         string        `mapstructure:"FX_RATE_SOURCE"`
+	FXRateFile           string        `mapstructure:"FX_RATE_FILE"`
+	FXRoundingMode       string        `mapstructure:"FX_ROUNDING_MODE"`
+	MaxDepositAmount     int64         `mapstructure:"MAX_DEPOSIT_AMOUNT"`
+	DailyDepositLimit    int64         `mapstructure:"DAILY_DEPOSIT_LIMIT"`
+	DailyWithdrawalLimit int64         `mapstructure:"DAILY_WITHDRAWAL_LIMIT"`
+	CashLimitRates       string        `mapstructure:"CASH_LIMIT_RATES"`
+	HoldExpiryInterval   time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	TraceExporter        string        `mapstructure:"TRACE_EXPORTER"`
+	TraceOTLPEndpoint    string        `mapstructure:"TRACE_OTLP_ENDPOINT"`
+	TraceSampleRatio     float64       `mapstructure:"TRACE_SAMPLE_RATIO"`
+	LogLevel             string        `mapstructure:"LOG_LEVEL"`
+	LogFormat            string        `mapstructure:"LOG_FORMAT"`
 }
 
 // LoadConfig reads configuration from file or OS variables.
@@ -41,7 +64,29 @@ func LoadConfig(path string) (config Config, err error) {
 		"DB_NAME",
 		"DB_HOST",
 		"SERVER_PORT",
+		"SERVER_READ_TIMEOUT",
+		"SERVER_WRITE_TIMEOUT",
+		"SERVER_IDLE_TIMEOUT",
+		"SHUTDOWN_DRAIN_DELAY",
+		"SHUTDOWN_GRACE_PERIOD",
 		"MIGRATIONS_FOLDER",
+		"TOKEN_TYPE",
+		"TOKEN_SIGNING_KEY",
+		"ACCESS_TOKEN_DURATION",
+		"REFRESH_TOKEN_DURATION",
+		"FX_RATE_SOURCE",
+		"FX_RATE_FILE",
+		"FX_ROUNDING_MODE",
+		"MAX_DEPOSIT_AMOUNT",
+		"DAILY_DEPOSIT_LIMIT",
+		"DAILY_WITHDRAWAL_LIMIT",
+		"CASH_LIMIT_RATES",
+		"HOLD_EXPIRY_INTERVAL",
+		"TRACE_EXPORTER",
+		"TRACE_OTLP_ENDPOINT",
+		"TRACE_SAMPLE_RATIO",
+		"LOG_LEVEL",
+		"LOG_FORMAT",
 	}
 
 	// Map all environment variables to viper keys in a loop
@@ -51,13 +96,41 @@ func LoadConfig(path string) (config Config, err error) {
 		}
 	}
 
+	// Defaults for optional settings
+	viper.SetDefault("TOKEN_TYPE", "paseto")
+	// Requests still running once the grace period is over are cut short on shutdown
+	viper.SetDefault("SERVER_READ_TIMEOUT", "10s")
+	viper.SetDefault("SERVER_WRITE_TIMEOUT", "30s")
+	viper.SetDefault("SERVER_IDLE_TIMEOUT", "2m")
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "5s")
+	viper.SetDefault("SHUTDOWN_GRACE_PERIOD", "20s")
+	viper.SetDefault("ACCESS_TOKEN_DURATION", "15m")
+	viper.SetDefault("REFRESH_TOKEN_DURATION", "24h")
+	viper.SetDefault("FX_RATE_SOURCE", "none")
+	viper.SetDefault("FX_RATE_FILE", "fx_rates.json")
+	viper.SetDefault("FX_ROUNDING_MODE", "half_even")
+	// Cash limits are in whole US dollars, zero disables them. CASH_LIMIT_RATES gives the units of
+	// each currency worth one dollar, a currency without a rate is limited to the same number of units.
+	viper.SetDefault("MAX_DEPOSIT_AMOUNT", 10000)
+	viper.SetDefault("DAILY_DEPOSIT_LIMIT", 20000)
+	viper.SetDefault("DAILY_WITHDRAWAL_LIMIT", 5000)
+	viper.SetDefault("CASH_LIMIT_RATES", "CAD:1.35,EUR:0.9,GBP:0.8,JPY:150,KWD:0.3")
+	// Expired holds are also released whenever their account is debited, zero disables the sweep
+	viper.SetDefault("HOLD_EXPIRY_INTERVAL", "1m")
+	// Spans are only recorded once an exporter is configured, the OTLP endpoint defaults to localhost:4318
+	viper.SetDefault("TRACE_EXPORTER", "none")
+	viper.SetDefault("TRACE_SAMPLE_RATIO", 1.0)
+	// Logs are JSON lines, LOG_FORMAT=text is easier to read during development
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "json")
+
 	// Enable automatic environment variable lookup
 	viper.AutomaticEnv()
 
 	// New implementation
 	// Attempt to read the config file
 	if err = viper.ReadInConfig(); err != nil {
-		log.Printf("Config warning: %v. Using environment variables instead.", err)
+		slog.Warn("Config file not read, using environment variables instead", slog.Any("error", err))
 	}
 
 	// Check for missing required variables
@@ -70,9 +143,8 @@ func LoadConfig(path string) (config Config, err error) {
 
 	// Log missing environment variables
 	if len(missingVars) > 0 {
-		log.Printf(
-			"Config warning: Environment variables %v are missing or empty.",
-			strings.Join(missingVars, ", "),
+		slog.Warn("Environment variables are missing or empty",
+			slog.String("variables", strings.Join(missingVars, ", ")),
 		)
 	}
 
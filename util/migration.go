@@ -1,10 +1,12 @@
 package util
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"sync"
 	"time"
 
 	_ "github.com/aronreisx/bubblebank/db/migrations"
@@ -12,12 +14,18 @@ import (
 	"github.com/pressly/goose/v3"
 )
 
+// setDialect configures goose for PostgreSQL. goose keeps the dialect in a global,
+// so it is only set once rather than by every readiness probe.
+var setDialect = sync.OnceValue(func() error {
+	return goose.SetDialect("postgres")
+})
+
 // RunDBMigration runs database migrations using goose with in-code Go migrations
 func RunDBMigration(migrationPath string, dbURL string) error {
 	var db *sql.DB
 	var err error
 
-	log.Println("Attempting to connect to database for migrations...")
+	slog.Info("Attempting to connect to database for migrations")
 
 	maxRetries := 5
 	retryDelay := 3 * time.Second
@@ -25,7 +33,7 @@ func RunDBMigration(migrationPath string, dbURL string) error {
 	for i := range maxRetries {
 		db, err = sql.Open("pgx", dbURL)
 		if err != nil {
-			log.Printf("Database connection attempt %d failed: %v", i+1, err)
+			slog.Warn("Database connection attempt failed", slog.Int("attempt", i+1), slog.Any("error", err))
 			time.Sleep(retryDelay)
 			continue
 		}
@@ -33,45 +41,78 @@ func RunDBMigration(migrationPath string, dbURL string) error {
 		// Check if connection is actually working
 		err = db.Ping()
 		if err != nil {
-			log.Printf("Database ping attempt %d failed: %v", i+1, err)
+			slog.Warn("Database ping attempt failed", slog.Int("attempt", i+1), slog.Any("error", err))
 			if err := db.Close(); err != nil {
-				log.Printf("Error closing database connection: %v", err)
+				slog.Error("Error closing database connection", slog.Any("error", err))
 			}
 			time.Sleep(retryDelay)
 			continue
 		}
 
-		log.Printf("Successfully connected to database on attempt %d", i+1)
+		slog.Info("Successfully connected to database", slog.Int("attempt", i+1))
 		break
 	}
 
 	if err != nil {
-		log.Printf("Database connection failed after %d attempts: %v", maxRetries, err)
+		slog.Error("Database connection failed", slog.Int("attempts", maxRetries), slog.Any("error", err))
 		return fmt.Errorf("database connection unavailable")
 	}
 	defer func() {
 		if err := db.Close(); err != nil {
-			log.Printf("Error closing database connection: %v", err)
+			slog.Error("Error closing database connection", slog.Any("error", err))
 		}
 	}()
 
-	if err := goose.SetDialect("postgres"); err != nil {
+	if err := setDialect(); err != nil {
 		return fmt.Errorf("failed to set dialect: %w", err)
 	}
 
-	if migrationPath == "" || !directoryExists(migrationPath) {
-		migrationPath = "."
-		log.Printf("Using current directory for migrations as fallback since the migrations path was not provided")
+	dir := migrationDir(migrationPath)
+	if dir != migrationPath {
+		slog.Warn("Using current directory for migrations as fallback since the migrations path was not provided")
 	}
 
-	if err := goose.Up(db, migrationPath); err != nil {
+	if err := goose.Up(db, dir); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	log.Println("DB migration completed successfully")
+	slog.Info("DB migration completed successfully")
 	return nil
 }
 
+// MigrationVersions returns the version the database is migrated to
+// and the latest version of the migrations built into the service
+func MigrationVersions(ctx context.Context, db *sql.DB, migrationPath string) (current, latest int64, err error) {
+	if err := setDialect(); err != nil {
+		return 0, 0, fmt.Errorf("failed to set dialect: %w", err)
+	}
+
+	migrations, err := goose.CollectMigrations(migrationDir(migrationPath), 0, goose.MaxVersion)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	current, err = goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get database version: %w", err)
+	}
+
+	return current, last.Version, nil
+}
+
+// migrationDir returns the directory goose looks for migrations in.
+// The migrations are compiled in, so any existing directory will do.
+func migrationDir(migrationPath string) string {
+	if migrationPath == "" || !directoryExists(migrationPath) {
+		return "."
+	}
+	return migrationPath
+}
+
 func directoryExists(path string) bool {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+
+	"github.com/aronreisx/bubblebank/currency"
 )
 
 const alphabet = "abcdefghijklmnopqrstuvwxyz"
@@ -40,7 +42,7 @@ func RandomMoney() int64 {
 
 // RandomCurrency generates a random currency code
 func RandomCurrency() string {
-	currencies := []string{"USD", "EUR", "CAD"}
+	currencies := currency.Codes()
 	n := len(currencies)
 	index, _ := rand.Int(rand.Reader, big.NewInt(int64(n)))
 	return currencies[index.Int64()]
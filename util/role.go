@@ -0,0 +1,9 @@
+package util
+
+// Roles a user can have. Depositors only see their own accounts,
+// bankers and admins can look up any account.
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+	AdminRole     = "admin"
+)